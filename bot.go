@@ -4,19 +4,21 @@ package tgbotapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/technoweenie/multipartstreamer"
 )
 
 // BotAPI allows you to interact with the Telegram Bot API.
@@ -27,6 +29,41 @@ type BotAPI struct {
 
 	Self   User         `json:"-"`
 	Client *http.Client `json:"-"`
+
+	// UploadProgress is called while uploading files, unless the config
+	// being sent has its own UploadProgress.
+	UploadProgress UploadProgressFunc `json:"-"`
+
+	// FileIDCache remembers the file IDs of uploaded files. If it is set,
+	// sending the same content again sends the cached file ID instead of
+	// uploading it.
+	FileIDCache FileIDCache `json:"-"`
+
+	// Logger receives the log output of the bot, with the token removed.
+	// If it is nil, the logger set by SetLogger is used.
+	Logger StructuredLogger `json:"-"`
+
+	// Interceptors are called in order around every request, see
+	// Interceptor.
+	Interceptors []Interceptor `json:"-"`
+
+	// Instrumentation is notified about every request, for example to
+	// collect metrics or traces.
+	Instrumentation Instrumentation `json:"-"`
+
+	// StrictDecoding logs a warning for fields of results unknown to this
+	// library, which helps noticing changes of the Bot API while debugging.
+	// Results are decoded as usual either way.
+	StrictDecoding bool `json:"-"`
+
+	// MaxDownloadSize is the largest file in bytes DownloadFile accepts.
+	// If it is zero, MaxDownloadFileSize is used.
+	MaxDownloadSize int64 `json:"-"`
+
+	// ChatActionInterval is how often StartChatAction repeats the action.
+	// If it is zero, DefaultChatActionInterval is used.
+	ChatActionInterval time.Duration `json:"-"`
+
 	shutdownChannel chan interface{}
 }
 
@@ -43,9 +80,9 @@ func NewBotAPI(token string) (*BotAPI, error) {
 // It requires a token, provided by @BotFather on Telegram.
 func NewBotAPIWithClient(token string, client *http.Client) (*BotAPI, error) {
 	bot := &BotAPI{
-		Token:  token,
-		Client: client,
-		Buffer: 100,
+		Token:           token,
+		Client:          client,
+		Buffer:          100,
 		shutdownChannel: make(chan interface{}),
 	}
 
@@ -61,35 +98,124 @@ func NewBotAPIWithClient(token string, client *http.Client) (*BotAPI, error) {
 
 // MakeRequest makes a request to a specific endpoint with our token.
 func (bot *BotAPI) MakeRequest(endpoint string, params url.Values) (APIResponse, error) {
+	return bot.MakeRequestContext(context.Background(), endpoint, params)
+}
+
+// MakeRequestContext makes a request to a specific endpoint with our token,
+// which is aborted when ctx is done.
+func (bot *BotAPI) MakeRequestContext(ctx context.Context, endpoint string, params url.Values) (APIResponse, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+
+	req := &InterceptedRequest{
+		Context: ctx,
+		Method:  endpoint,
+		Values:  params,
+		Header:  http.Header{},
+	}
+
+	return bot.intercept(req, func(req *InterceptedRequest) (APIResponse, error) {
+		body := req.Values.Encode()
+
+		return bot.doRequest(req.Context, req.Method, req.Header, strings.NewReader(body), int64(len(body)), "application/x-www-form-urlencoded", false)
+	})
+}
+
+// doRequest sends body to endpoint and decodes the APIResponse, notifying
+// the Instrumentation of the bot if it has one.
+//
+// size is the length of body, or -1 if it is unknown.
+func (bot *BotAPI) doRequest(ctx context.Context, endpoint string, header http.Header, body io.Reader, size int64, contentType string, upload bool) (APIResponse, error) {
+	if bot.Instrumentation == nil {
+		return bot.roundTrip(ctx, endpoint, header, body, size, contentType, &RequestInfo{})
+	}
+
+	info := RequestInfo{
+		Method:  endpoint,
+		Upload:  upload,
+		Start:   time.Now(),
+		Retries: retryCount(ctx),
+	}
+
+	ctx = bot.Instrumentation.StartRequest(ctx, endpoint)
+
+	sent := &countingReader{r: body}
+	apiResp, err := bot.roundTrip(ctx, endpoint, header, sent, size, contentType, &info)
+
+	info.Duration = time.Since(info.Start)
+	info.BytesSent = size
+	if size < 0 {
+		info.BytesSent = sent.count()
+	}
+	info.Ok = apiResp.Ok
+	info.ErrorCode = apiResp.ErrorCode
+	info.Err = err
+
+	bot.Instrumentation.FinishRequest(ctx, info)
+
+	return apiResp, err
+}
+
+// roundTrip sends body to endpoint and decodes the APIResponse. The status
+// code and size of the response are stored in info.
+func (bot *BotAPI) roundTrip(ctx context.Context, endpoint string, header http.Header, body io.Reader, size int64, contentType string, info *RequestInfo) (APIResponse, error) {
 	method := fmt.Sprintf(APIEndpoint, bot.Token, endpoint)
 
-	resp, err := bot.Client.PostForm(method, params)
+	req, err := http.NewRequest("POST", method, body)
 	if err != nil {
 		return APIResponse{}, err
 	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := bot.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return APIResponse{}, bot.redactURLError(err)
+	}
 	defer resp.Body.Close()
 
+	info.StatusCode = resp.StatusCode
+
+	received := &countingReader{r: resp.Body}
+	defer func() {
+		info.BytesReceived = received.count()
+	}()
+
 	var apiResp APIResponse
-	bytes, err := bot.decodeAPIResponse(resp.Body, &apiResp)
+	bytes, err := bot.decodeAPIResponse(received, &apiResp)
 	if err != nil {
 		return apiResp, err
 	}
 
 	if bot.Debug {
-		log.Printf("%s resp: %s", endpoint, bytes)
+		bot.logEvent(LogLevelDebug, "response", "method", endpoint, "body", string(bytes))
 	}
 
 	if !apiResp.Ok {
-		parameters := ResponseParameters{}
-		if apiResp.Parameters != nil {
-			parameters = *apiResp.Parameters
-		}
-		return apiResp, Error{apiResp.Description, parameters}
+		return apiResp, responseError(apiResp)
 	}
 
 	return apiResp, nil
 }
 
+// responseError returns the Error described by a response which is not
+// Ok.
+func responseError(resp APIResponse) error {
+	parameters := ResponseParameters{}
+	if resp.Parameters != nil {
+		parameters = *resp.Parameters
+	}
+
+	return Error{resp.Description, parameters}
+}
+
 // decodeAPIResponse decode response and return slice of bytes if debug enabled.
 // If debug disabled, just decode http.Response.Body stream to APIResponse struct
 // for efficient memory usage
@@ -114,15 +240,66 @@ func (bot *BotAPI) decodeAPIResponse(responseBody io.Reader, resp *APIResponse)
 	return data, nil
 }
 
+// decodeResult decodes the result of a request to method into v.
+//
+// If StrictDecoding is enabled, fields of the result unknown to v are
+// logged as a DecodeError. Errors are returned as a DecodeError.
+func (bot *BotAPI) decodeResult(method string, resp APIResponse, v interface{}) error {
+	if err := json.Unmarshal(resp.Result, v); err != nil {
+		return DecodeError{Method: method, Payload: excerpt(resp.Result), Err: err}
+	}
+
+	if bot.StrictDecoding {
+		bot.checkUnknownFields(method, resp, v)
+	}
+
+	return nil
+}
+
+// checkUnknownFields decodes the result of a request to method again into
+// a new value of the type of v, logging a warning if it contains fields
+// unknown to v.
+func (bot *BotAPI) checkUnknownFields(method string, resp APIResponse, v interface{}) {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(resp.Result))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(reflect.New(t.Elem()).Interface()); err != nil {
+		bot.logEvent(LogLevelWarn, "unknown fields in result", "method", method,
+			"error", DecodeError{Method: method, Payload: excerpt(resp.Result), Err: err})
+	}
+}
+
+// decodeMessage decodes the Message returned by a request to method.
+//
+// Methods editing inline messages return true instead of a Message, in
+// which case an empty Message is returned.
+func (bot *BotAPI) decodeMessage(method string, resp APIResponse) (Message, error) {
+	var message Message
+	if string(resp.Result) == "true" {
+		return message, nil
+	}
+
+	err := bot.decodeResult(method, resp, &message)
+
+	return message, err
+}
+
 // makeMessageRequest makes a request to a method that returns a Message.
-func (bot *BotAPI) makeMessageRequest(endpoint string, params url.Values) (Message, error) {
-	resp, err := bot.MakeRequest(endpoint, params)
+func (bot *BotAPI) makeMessageRequest(ctx context.Context, endpoint string, params url.Values) (Message, error) {
+	resp, err := bot.MakeRequestContext(ctx, endpoint, params)
 	if err != nil {
 		return Message{}, err
 	}
 
-	var message Message
-	json.Unmarshal(resp.Result, &message)
+	message, err := bot.decodeMessage(endpoint, resp)
+	if err != nil {
+		return Message{}, err
+	}
 
 	bot.debugLog(endpoint, params, message)
 
@@ -135,93 +312,289 @@ func (bot *BotAPI) makeMessageRequest(endpoint string, params url.Values) (Messa
 // File should be a string to a file path, a FileBytes struct,
 // a FileReader struct, or a url.URL.
 //
-// Note that if your FileReader has a size set to -1, it will read
-// the file into memory to calculate a size.
+// The file is streamed to Telegram as it is read, so a FileReader of
+// unknown size is never buffered in memory.
 func (bot *BotAPI) UploadFile(endpoint string, params map[string]string, fieldname string, file interface{}) (APIResponse, error) {
-	ms := multipartstreamer.New()
+	return bot.UploadFiles(endpoint, params, []RequestFile{{Name: fieldname, File: file}})
+}
 
-	switch f := file.(type) {
-	case string:
-		ms.WriteFields(params)
+// UploadFiles makes a request to the API with multiple files.
+//
+// Each RequestFile is sent as a separate field of a multipart request,
+// along with the params. The request body is written while it is being
+// sent using chunked transfer encoding, so files are never read into
+// memory as a whole.
+func (bot *BotAPI) UploadFiles(endpoint string, params map[string]string, files []RequestFile) (APIResponse, error) {
+	return bot.UploadFilesContext(context.Background(), endpoint, params, files)
+}
 
-		fileHandle, err := os.Open(f)
-		if err != nil {
-			return APIResponse{}, err
+// UploadFilesContext is like UploadFiles, but the upload is stopped
+// as soon as ctx is done.
+//
+// Progress is reported to the bot's UploadProgress, if set.
+func (bot *BotAPI) UploadFilesContext(ctx context.Context, endpoint string, params map[string]string, files []RequestFile) (APIResponse, error) {
+	return bot.uploadFiles(ctx, endpoint, params, files, bot.UploadProgress)
+}
+
+func (bot *BotAPI) uploadFiles(ctx context.Context, endpoint string, params map[string]string, files []RequestFile, progress UploadProgressFunc) (APIResponse, error) {
+	req := &InterceptedRequest{
+		Context: ctx,
+		Method:  endpoint,
+		Params:  params,
+		Files:   files,
+		Header:  http.Header{},
+	}
+
+	return bot.intercept(req, func(req *InterceptedRequest) (APIResponse, error) {
+		r, w := io.Pipe()
+		defer r.Close()
+
+		m := multipart.NewWriter(w)
+		upload := &upload{ctx: req.Context, progress: progress}
+		if progress != nil {
+			upload.total = filesSize(req.Files)
 		}
-		defer fileHandle.Close()
 
-		fi, err := os.Stat(f)
-		if err != nil {
-			return APIResponse{}, err
+		// The request is only chunked if the size of a file is unknown.
+		size := multipartSize(m.Boundary(), req.Params, req.Files)
+
+		go func() {
+			w.CloseWithError(writeMultipart(m, req.Params, req.Files, upload))
+		}()
+
+		return bot.doRequest(req.Context, req.Method, req.Header, r, size, m.FormDataContentType(), true)
+	})
+}
+
+// writeMultipart writes params and files to m and closes it.
+func writeMultipart(m *multipart.Writer, params map[string]string, files []RequestFile, upload *upload) error {
+	for key, value := range params {
+		if err := m.WriteField(key, value); err != nil {
+			return err
 		}
+	}
 
-		ms.WriteReader(fieldname, fileHandle.Name(), fi.Size(), fileHandle)
-	case FileBytes:
-		ms.WriteFields(params)
+	for _, file := range files {
+		if err := writeMultipartFile(m, file, upload); err != nil {
+			return err
+		}
+	}
 
-		buf := bytes.NewBuffer(f.Bytes)
-		ms.WriteReader(fieldname, f.Name, int64(len(f.Bytes)), buf)
-	case FileReader:
-		ms.WriteFields(params)
+	return m.Close()
+}
+
+// multipartSize returns the length of the body writeMultipart writes with
+// boundary, or -1 if the size of a file is unknown.
+func multipartSize(boundary string, params map[string]string, files []RequestFile) int64 {
+	counter := &countingWriter{}
+	m := multipart.NewWriter(counter)
+	if err := m.SetBoundary(boundary); err != nil {
+		return -1
+	}
+
+	for key, value := range params {
+		if err := m.WriteField(key, value); err != nil {
+			return -1
+		}
+	}
+
+	for _, file := range files {
+		var name string
+		var size int64
 
-		if f.Size != -1 {
-			ms.WriteReader(fieldname, f.Name, f.Size, f.Reader)
+		switch f := file.File.(type) {
+		case string, FilePath:
+			fi, err := os.Stat(fmt.Sprint(f))
+			if err != nil {
+				return -1
+			}
+			name, size = filepath.Base(fmt.Sprint(f)), fi.Size()
+		case FileBytes:
+			name, size = f.Name, int64(len(f.Bytes))
+		case FileReader:
+			if f.Size <= 0 {
+				return -1
+			}
+			name, size = f.Name, f.Size
+		case url.URL:
+			if err := m.WriteField(file.Name, f.String()); err != nil {
+				return -1
+			}
+			continue
+		default:
+			return -1
+		}
 
-			break
+		if _, err := m.CreateFormFile(file.Name, name); err != nil {
+			return -1
 		}
+		counter.n += size
+	}
+
+	if err := m.Close(); err != nil {
+		return -1
+	}
+
+	return counter.n
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
 
-		data, err := ioutil.ReadAll(f.Reader)
+// writeMultipartFile writes the contents of file as a part of w.
+func writeMultipartFile(w *multipart.Writer, file RequestFile, upload *upload) error {
+	switch f := file.File.(type) {
+	case string, FilePath:
+		path := fmt.Sprint(f)
+
+		fileHandle, err := os.Open(path)
 		if err != nil {
-			return APIResponse{}, err
+			return err
 		}
+		defer fileHandle.Close()
 
-		buf := bytes.NewBuffer(data)
+		part, err := w.CreateFormFile(file.Name, filepath.Base(path))
+		if err != nil {
+			return err
+		}
 
-		ms.WriteReader(fieldname, f.Name, int64(len(data)), buf)
-	case url.URL:
-		params[fieldname] = f.String()
+		_, err = io.Copy(part, upload.reader(fileHandle))
+		return err
+	case FileBytes:
+		part, err := w.CreateFormFile(file.Name, f.Name)
+		if err != nil {
+			return err
+		}
 
-		ms.WriteFields(params)
+		_, err = io.Copy(part, upload.reader(bytes.NewReader(f.Bytes)))
+		return err
+	case FileReader:
+		part, err := w.CreateFormFile(file.Name, f.Name)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(part, upload.reader(f.Reader))
+		return err
+	case url.URL:
+		return w.WriteField(file.Name, f.String())
 	default:
-		return APIResponse{}, errors.New(ErrBadFileType)
+		return errors.New(ErrBadFileType)
 	}
+}
 
-	method := fmt.Sprintf(APIEndpoint, bot.Token, endpoint)
+// upload keeps track of the files written to a multipart request.
+type upload struct {
+	ctx      context.Context
+	progress UploadProgressFunc
+	sent     int64
+	total    int64
+}
 
-	req, err := http.NewRequest("POST", method, nil)
-	if err != nil {
-		return APIResponse{}, err
-	}
+// reader wraps r to stop reading once the upload is cancelled and to
+// report progress.
+func (u *upload) reader(r io.Reader) io.Reader {
+	return &uploadReader{upload: u, reader: r}
+}
 
-	ms.SetupRequest(req)
+type uploadReader struct {
+	upload *upload
+	reader io.Reader
+}
 
-	res, err := bot.Client.Do(req)
-	if err != nil {
-		return APIResponse{}, err
+func (r *uploadReader) Read(p []byte) (int, error) {
+	if err := r.upload.ctx.Err(); err != nil {
+		return 0, err
 	}
-	defer res.Body.Close()
 
-	bytes, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return APIResponse{}, err
+	n, err := r.reader.Read(p)
+	if n > 0 && r.upload.progress != nil {
+		r.upload.sent += int64(n)
+		r.upload.progress(r.upload.sent, r.upload.total)
 	}
 
-	if bot.Debug {
-		log.Println(string(bytes))
-	}
+	return n, err
+}
 
-	var apiResp APIResponse
+// filesSize returns the total size of files, or -1 if it is unknown.
+func filesSize(files []RequestFile) int64 {
+	var total int64
 
-	err = json.Unmarshal(bytes, &apiResp)
-	if err != nil {
-		return APIResponse{}, err
+	for _, file := range files {
+		switch f := file.File.(type) {
+		case string, FilePath:
+			fi, err := os.Stat(fmt.Sprint(f))
+			if err != nil {
+				return -1
+			}
+			total += fi.Size()
+		case FileBytes:
+			total += int64(len(f.Bytes))
+		case FileReader:
+			if f.Size < 0 {
+				return -1
+			}
+			total += f.Size
+		}
 	}
 
-	if !apiResp.Ok {
-		return APIResponse{}, errors.New(apiResp.Description)
+	return total
+}
+
+// DefaultChatActionInterval is how often StartChatAction repeats the
+// action, slightly less than the five seconds an action is shown for.
+const DefaultChatActionInterval = 4 * time.Second
+
+// StartChatAction sends config now and then every ChatActionInterval,
+// until ctx is done or the returned stop function is called. Once stop
+// returns, no more actions are sent.
+//
+// It is meant to keep an action such as ChatUploadVideo visible while
+// a long upload is in progress:
+//
+//	stop := bot.StartChatAction(ctx, NewChatAction(chatID, ChatUploadVideo))
+//	defer stop()
+//	bot.SendContext(ctx, video)
+func (bot *BotAPI) StartChatAction(ctx context.Context, config ChatActionConfig) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	interval := bot.ChatActionInterval
+	if interval <= 0 {
+		interval = DefaultChatActionInterval
 	}
 
-	return apiResp, nil
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := bot.SendContext(ctx, config); err != nil && ctx.Err() == nil {
+				bot.logEvent(LogLevelWarn, "failed to send chat action", "action", config.Action, "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
 }
 
 // GetFileDirectURL returns direct URL to file
@@ -237,6 +610,129 @@ func (bot *BotAPI) GetFileDirectURL(fileID string) (string, error) {
 	return file.Link(bot.Token), nil
 }
 
+// DownloadFile downloads the file with fileID from Telegram.
+//
+// The file is streamed through the bot's Client and the returned reader
+// must be closed. Reading fails with a FileTooBigError once more than
+// MaxDownloadSize bytes are read, or a FileSizeMismatchError if the file
+// does not have the size reported by Telegram.
+func (bot *BotAPI) DownloadFile(fileID string) (io.ReadCloser, File, error) {
+	return bot.DownloadFileContext(context.Background(), fileID)
+}
+
+// DownloadFileContext downloads the file with fileID from Telegram. Looking
+// up the file and the download are aborted when ctx is done.
+func (bot *BotAPI) DownloadFileContext(ctx context.Context, fileID string) (io.ReadCloser, File, error) {
+	limit := bot.MaxDownloadSize
+	if limit <= 0 {
+		limit = MaxDownloadFileSize
+	}
+
+	var file File
+	if err := bot.RequestResultContext(ctx, FileConfig{fileID}, &file); err != nil {
+		return nil, file, err
+	}
+
+	if int64(file.FileSize) > limit {
+		return nil, file, FileTooBigError{FileID: fileID, Size: int64(file.FileSize), Limit: limit}
+	}
+
+	if file.FilePath == "" {
+		return nil, file, errors.New(ErrNoFilePath)
+	}
+
+	req, err := http.NewRequest("GET", file.Link(bot.Token), nil)
+	if err != nil {
+		return nil, file, err
+	}
+
+	resp, err := bot.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, file, bot.redactURLError(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, file, Error{Message: "file download failed: " + resp.Status}
+	}
+
+	if resp.ContentLength > limit {
+		resp.Body.Close()
+		return nil, file, FileTooBigError{FileID: fileID, Size: resp.ContentLength, Limit: limit}
+	}
+
+	return &downloadReader{
+		body:     resp.Body,
+		fileID:   fileID,
+		expected: int64(file.FileSize),
+		limit:    limit,
+	}, file, nil
+}
+
+// DownloadFileTo downloads the file with fileID from Telegram to path.
+//
+// The file is written to a temporary file next to path first, so path is
+// only created once the download succeeded.
+func (bot *BotAPI) DownloadFileTo(fileID, path string) (File, error) {
+	body, file, err := bot.DownloadFile(fileID)
+	if err != nil {
+		return file, err
+	}
+	defer body.Close()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return file, err
+	}
+
+	_, err = io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return file, err
+	}
+
+	return file, nil
+}
+
+// downloadReader reads a downloaded file and checks its size.
+type downloadReader struct {
+	body     io.ReadCloser
+	fileID   string
+	expected int64
+	limit    int64
+	read     int64
+}
+
+func (r *downloadReader) Read(p []byte) (int, error) {
+	// Read one byte beyond the limit to notice files which are too big.
+	if remaining := r.limit + 1 - r.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.body.Read(p)
+	r.read += int64(n)
+
+	if r.read > r.limit {
+		return n, FileTooBigError{FileID: r.fileID, Size: r.read, Limit: r.limit}
+	}
+
+	if err == io.EOF && r.expected > 0 && r.read != r.expected {
+		return n, FileSizeMismatchError{FileID: r.fileID, Expected: r.expected, Actual: r.read}
+	}
+
+	return n, err
+}
+
+func (r *downloadReader) Close() error {
+	return r.body.Close()
+}
+
 // GetMe fetches the currently authenticated bot.
 //
 // This method is called upon creation to validate the token,
@@ -245,34 +741,185 @@ func (bot *BotAPI) GetFileDirectURL(fileID string) (string, error) {
 func (bot *BotAPI) GetMe() (User, error) {
 	resp, err := bot.MakeRequest("getMe", nil)
 	if err != nil {
-		return User{}, err
+		return User{}, err
+	}
+
+	var user User
+	if err := bot.decodeResult("getMe", resp, &user); err != nil {
+		return User{}, err
+	}
+
+	bot.debugLog("getMe", nil, user)
+
+	return user, nil
+}
+
+// IsMessageToMe returns true if message directed to this bot.
+//
+// It requires the Message.
+func (bot *BotAPI) IsMessageToMe(message Message) bool {
+	return strings.Contains(message.Text, "@"+bot.Self.UserName)
+}
+
+// Send will send a Chattable item to Telegram.
+//
+// It requires the Chattable to send.
+//
+// A MediaGroupConfig results in multiple messages, of which only the
+// first is returned. Use SendMediaGroup to get all of them.
+func (bot *BotAPI) Send(c Chattable) (Message, error) {
+	return bot.SendContext(context.Background(), c)
+}
+
+// SendContext is like Send, but stops sending, including any file upload
+// in progress, as soon as ctx is done.
+func (bot *BotAPI) SendContext(ctx context.Context, c Chattable) (Message, error) {
+	switch c.(type) {
+	case Fileable:
+		return bot.sendFile(ctx, c.(Fileable))
+	case MediaGroupConfig:
+		var messages []Message
+		if err := bot.RequestResultContext(ctx, c, &messages); err != nil || len(messages) == 0 {
+			return Message{}, err
+		}
+
+		return messages[0], nil
+	default:
+		return bot.sendChattable(ctx, c)
+	}
+}
+
+// Request sends a Chattable to Telegram and returns the APIResponse.
+//
+// Unlike Send, it does not expect a Message as the result, so it works
+// with every config. Files of a Fileable, a MediaGroupConfig or an
+// EditMessageMediaConfig are uploaded.
+func (bot *BotAPI) Request(c Chattable) (APIResponse, error) {
+	return bot.RequestContext(context.Background(), c)
+}
+
+// RequestContext is like Request, but stops the request, including any
+// file upload in progress, as soon as ctx is done.
+func (bot *BotAPI) RequestContext(ctx context.Context, c Chattable) (APIResponse, error) {
+	resp, v, err := bot.request(ctx, c)
+	if err != nil {
+		return resp, err
+	}
+
+	bot.debugLog(c.method(), v, nil)
+
+	return resp, nil
+}
+
+// request sends c to Telegram without logging it, so callers decoding
+// the result can log it once along with the values sent, which are nil
+// for uploads.
+func (bot *BotAPI) request(ctx context.Context, c Chattable) (APIResponse, url.Values, error) {
+	var files []RequestFile
+	var progress UploadProgressFunc
+
+	switch config := c.(type) {
+	case Fileable:
+		files = config.files()
+		progress = config.uploadProgress()
+	case MediaGroupConfig:
+		files = config.files()
+	case EditMessageMediaConfig:
+		files = config.files()
+	}
+
+	if len(files) == 0 {
+		v, err := c.values()
+		if err != nil {
+			return APIResponse{}, nil, err
+		}
+
+		resp, err := bot.MakeRequestContext(ctx, c.method(), v)
+		return resp, v, err
+	}
+
+	params, err := requestParams(c)
+	if err != nil {
+		return APIResponse{}, nil, err
+	}
+
+	if progress == nil {
+		progress = bot.UploadProgress
+	}
+
+	resp, err := bot.uploadFiles(ctx, c.method(), params, files, progress)
+	return resp, nil, err
+}
+
+// RequestResult sends a Chattable to Telegram like Request and decodes
+// the result into result, which must be a pointer to a type matching the
+// result of the method.
+func (bot *BotAPI) RequestResult(c Chattable, result interface{}) error {
+	return bot.RequestResultContext(context.Background(), c, result)
+}
+
+// RequestResultContext is like RequestResult, but stops the request as
+// soon as ctx is done.
+func (bot *BotAPI) RequestResultContext(ctx context.Context, c Chattable, result interface{}) error {
+	resp, v, err := bot.request(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if err := bot.decodeResult(c.method(), resp, result); err != nil {
+		return err
+	}
+
+	bot.debugLog(c.method(), v, result)
+
+	return nil
+}
+
+// requestResult makes a request to method with v and decodes the result
+// into result. It is used by methods sharing a config type, which therefore
+// can not be a Chattable.
+func (bot *BotAPI) requestResult(method string, v url.Values, result interface{}) error {
+	resp, err := bot.MakeRequest(method, v)
+	if err != nil {
+		return err
+	}
+
+	if err := bot.decodeResult(method, resp, result); err != nil {
+		return err
+	}
+
+	bot.debugLog(method, v, result)
+
+	return nil
+}
+
+// requestMessage sends a Chattable to Telegram and returns the Message it
+// results in.
+func (bot *BotAPI) requestMessage(ctx context.Context, c Chattable) (Message, error) {
+	resp, v, err := bot.request(ctx, c)
+	if err != nil {
+		return Message{}, err
 	}
 
-	var user User
-	json.Unmarshal(resp.Result, &user)
+	message, err := bot.decodeMessage(c.method(), resp)
+	if err != nil {
+		return Message{}, err
+	}
 
-	bot.debugLog("getMe", nil, user)
+	bot.debugLog(c.method(), v, message)
 
-	return user, nil
+	return message, nil
 }
 
-// IsMessageToMe returns true if message directed to this bot.
+// SendMediaGroup sends a media group and returns the resulting messages.
 //
-// It requires the Message.
-func (bot *BotAPI) IsMessageToMe(message Message) bool {
-	return strings.Contains(message.Text, "@"+bot.Self.UserName)
-}
+// Media which is a FilePath, FileBytes or FileReader is uploaded as part
+// of the request.
+func (bot *BotAPI) SendMediaGroup(config MediaGroupConfig) ([]Message, error) {
+	var messages []Message
+	err := bot.RequestResult(config, &messages)
 
-// Send will send a Chattable item to Telegram.
-//
-// It requires the Chattable to send.
-func (bot *BotAPI) Send(c Chattable) (Message, error) {
-	switch c.(type) {
-	case Fileable:
-		return bot.sendFile(c.(Fileable))
-	default:
-		return bot.sendChattable(c)
-	}
+	return messages, err
 }
 
 // debugLog checks if the bot is currently running in debug mode, and if
@@ -280,73 +927,128 @@ func (bot *BotAPI) Send(c Chattable) (Message, error) {
 // debug log.
 func (bot *BotAPI) debugLog(context string, v url.Values, message interface{}) {
 	if bot.Debug {
-		log.Printf("%s req : %+v\n", context, v)
-		log.Printf("%s resp: %+v\n", context, message)
+		bot.logEvent(LogLevelDebug, "request", "method", context, "params", v, "result", message)
 	}
 }
 
-// sendExisting will send a Message with an existing file to Telegram.
-func (bot *BotAPI) sendExisting(method string, config Fileable) (Message, error) {
-	v, err := config.values()
+// uploadAndSend will send a Message with new files to Telegram.
+//
+// If the bot has a FileIDCache, a file uploaded before is sent by its
+// cached file ID instead.
+func (bot *BotAPI) uploadAndSend(ctx context.Context, method string, config Fileable) (Message, error) {
+	files := config.files()
 
-	if err != nil {
-		return Message{}, err
+	var cacheKey string
+	if bot.FileIDCache != nil && !config.useExistingFile() {
+		cacheKey = fileIDCacheKey(files[0].Name, files[0].File)
+	}
+
+	if cacheKey != "" {
+		if fileID, ok := bot.FileIDCache.Get(cacheKey); ok {
+			message, err := bot.sendCached(ctx, method, config, files, fileID)
+			if !isFileIDError(err) {
+				return message, err
+			}
+
+			// Telegram rejected the cached file ID, so upload it again.
+			if err := bot.FileIDCache.Delete(cacheKey); err != nil {
+				bot.logEvent(LogLevelWarn, "failed to delete cached file ID", "error", err)
+			}
+		}
 	}
 
-	message, err := bot.makeMessageRequest(method, v)
+	message, err := bot.requestMessage(ctx, config)
 	if err != nil {
 		return Message{}, err
 	}
 
+	if cacheKey != "" {
+		if fileID := messageFileID(message, files[0].Name); fileID != "" {
+			// The message was sent, so failing to cache its file ID is
+			// not an error of the request.
+			if err := bot.FileIDCache.Set(cacheKey, fileID); err != nil {
+				bot.logEvent(LogLevelWarn, "failed to cache file ID", "error", err)
+			}
+		}
+	}
+
 	return message, nil
 }
 
-// uploadAndSend will send a Message with a new file to Telegram.
-func (bot *BotAPI) uploadAndSend(method string, config Fileable) (Message, error) {
-	params, err := config.params()
+// sendCached sends config with fileID in place of the first of its files.
+func (bot *BotAPI) sendCached(ctx context.Context, method string, config Fileable, files []RequestFile, fileID string) (Message, error) {
+	v, err := config.values()
 	if err != nil {
 		return Message{}, err
 	}
 
-	file := config.getFile()
+	v.Set(files[0].Name, fileID)
+
+	if len(files) == 1 {
+		return bot.makeMessageRequest(ctx, method, v)
+	}
+
+	return bot.uploadMessage(ctx, method, valuesToParams(v), files[1:], config.uploadProgress())
+}
+
+// uploadMessage uploads files to method and returns the sent Message.
+func (bot *BotAPI) uploadMessage(ctx context.Context, method string, params map[string]string, files []RequestFile, progress UploadProgressFunc) (Message, error) {
+	if progress == nil {
+		progress = bot.UploadProgress
+	}
 
-	resp, err := bot.UploadFile(method, params, config.name(), file)
+	resp, err := bot.uploadFiles(ctx, method, params, files, progress)
 	if err != nil {
 		return Message{}, err
 	}
 
-	var message Message
-	json.Unmarshal(resp.Result, &message)
+	message, err := bot.decodeMessage(method, resp)
+	if err != nil {
+		return Message{}, err
+	}
 
 	bot.debugLog(method, nil, message)
 
 	return message, nil
 }
 
-// sendFile determines if the file is using an existing file or uploading
-// a new file, then sends it as needed.
-func (bot *BotAPI) sendFile(config Fileable) (Message, error) {
-	if config.useExistingFile() {
-		return bot.sendExisting(config.method(), config)
+// requestParams returns the params to upload alongside the files of c.
+//
+// If a Fileable uses an existing file, only other parts such as a
+// thumbnail are uploaded and the file ID is sent as a regular field.
+func requestParams(c Chattable) (map[string]string, error) {
+	switch config := c.(type) {
+	case Fileable:
+		if !config.useExistingFile() {
+			return config.params()
+		}
+	case MediaGroupConfig:
+		return config.params()
+	case EditMessageMediaConfig:
+		return config.params()
 	}
 
-	return bot.uploadAndSend(config.method(), config)
-}
-
-// sendChattable sends a Chattable.
-func (bot *BotAPI) sendChattable(config Chattable) (Message, error) {
-	v, err := config.values()
+	v, err := c.values()
 	if err != nil {
-		return Message{}, err
+		return nil, err
 	}
 
-	message, err := bot.makeMessageRequest(config.method(), v)
+	return valuesToParams(v), nil
+}
 
-	if err != nil {
-		return Message{}, err
+// sendFile determines if there are any files to upload, then sends the
+// config as needed.
+func (bot *BotAPI) sendFile(ctx context.Context, config Fileable) (Message, error) {
+	if len(config.files()) == 0 {
+		return bot.requestMessage(ctx, config)
 	}
 
-	return message, nil
+	return bot.uploadAndSend(ctx, config.method(), config)
+}
+
+// sendChattable sends a Chattable.
+func (bot *BotAPI) sendChattable(ctx context.Context, config Chattable) (Message, error) {
+	return bot.requestMessage(ctx, config)
 }
 
 // GetUserProfilePhotos gets a user's profile photos.
@@ -354,46 +1056,20 @@ func (bot *BotAPI) sendChattable(config Chattable) (Message, error) {
 // It requires UserID.
 // Offset and Limit are optional.
 func (bot *BotAPI) GetUserProfilePhotos(config UserProfilePhotosConfig) (UserProfilePhotos, error) {
-	v := url.Values{}
-	v.Add("user_id", strconv.Itoa(config.UserID))
-	if config.Offset != 0 {
-		v.Add("offset", strconv.Itoa(config.Offset))
-	}
-	if config.Limit != 0 {
-		v.Add("limit", strconv.Itoa(config.Limit))
-	}
-
-	resp, err := bot.MakeRequest("getUserProfilePhotos", v)
-	if err != nil {
-		return UserProfilePhotos{}, err
-	}
-
 	var profilePhotos UserProfilePhotos
-	json.Unmarshal(resp.Result, &profilePhotos)
+	err := bot.RequestResult(config, &profilePhotos)
 
-	bot.debugLog("GetUserProfilePhoto", v, profilePhotos)
-
-	return profilePhotos, nil
+	return profilePhotos, err
 }
 
 // GetFile returns a File which can download a file from Telegram.
 //
 // Requires FileID.
 func (bot *BotAPI) GetFile(config FileConfig) (File, error) {
-	v := url.Values{}
-	v.Add("file_id", config.FileID)
-
-	resp, err := bot.MakeRequest("getFile", v)
-	if err != nil {
-		return File{}, err
-	}
-
 	var file File
-	json.Unmarshal(resp.Result, &file)
+	err := bot.RequestResult(config, &file)
 
-	bot.debugLog("GetFile", v, file)
-
-	return file, nil
+	return file, err
 }
 
 // GetUpdates fetches updates.
@@ -404,6 +1080,11 @@ func (bot *BotAPI) GetFile(config FileConfig) (File, error) {
 // Set Timeout to a large number to reduce requests so you can get updates
 // instantly instead of having to wait between requests.
 func (bot *BotAPI) GetUpdates(config UpdateConfig) ([]Update, error) {
+	return bot.getUpdates(context.Background(), config)
+}
+
+// getUpdates fetches updates, aborting the request when ctx is done.
+func (bot *BotAPI) getUpdates(ctx context.Context, config UpdateConfig) ([]Update, error) {
 	v := url.Values{}
 	if config.Offset != 0 {
 		v.Add("offset", strconv.Itoa(config.Offset))
@@ -415,13 +1096,15 @@ func (bot *BotAPI) GetUpdates(config UpdateConfig) ([]Update, error) {
 		v.Add("timeout", strconv.Itoa(config.Timeout))
 	}
 
-	resp, err := bot.MakeRequest("getUpdates", v)
+	resp, err := bot.MakeRequestContext(ctx, "getUpdates", v)
 	if err != nil {
 		return []Update{}, err
 	}
 
 	var updates []Update
-	json.Unmarshal(resp.Result, &updates)
+	if err := bot.decodeResult("getUpdates", resp, &updates); err != nil {
+		return []Update{}, err
+	}
 
 	bot.debugLog("getUpdates", v, updates)
 
@@ -430,7 +1113,7 @@ func (bot *BotAPI) GetUpdates(config UpdateConfig) ([]Update, error) {
 
 // RemoveWebhook unsets the webhook.
 func (bot *BotAPI) RemoveWebhook() (APIResponse, error) {
-	return bot.MakeRequest("setWebhook", url.Values{})
+	return bot.Request(WebhookConfig{})
 }
 
 // SetWebhook sets a webhook.
@@ -440,41 +1123,14 @@ func (bot *BotAPI) RemoveWebhook() (APIResponse, error) {
 // If you do not have a legitimate TLS certificate, you need to include
 // your self signed certificate with the config.
 func (bot *BotAPI) SetWebhook(config WebhookConfig) (APIResponse, error) {
-
-	if config.Certificate == nil {
-		v := url.Values{}
-		v.Add("url", config.URL.String())
-		if config.MaxConnections != 0 {
-			v.Add("max_connections", strconv.Itoa(config.MaxConnections))
-		}
-
-		return bot.MakeRequest("setWebhook", v)
-	}
-
-	params := make(map[string]string)
-	params["url"] = config.URL.String()
-	if config.MaxConnections != 0 {
-		params["max_connections"] = strconv.Itoa(config.MaxConnections)
-	}
-
-	resp, err := bot.UploadFile("setWebhook", params, "certificate", config.Certificate)
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	return resp, nil
+	return bot.Request(config)
 }
 
 // GetWebhookInfo allows you to fetch information about a webhook and if
 // one currently is set, along with pending update count and error messages.
 func (bot *BotAPI) GetWebhookInfo() (WebhookInfo, error) {
-	resp, err := bot.MakeRequest("getWebhookInfo", url.Values{})
-	if err != nil {
-		return WebhookInfo{}, err
-	}
-
 	var info WebhookInfo
-	err = json.Unmarshal(resp.Result, &info)
+	err := bot.RequestResult(GetWebhookInfoConfig{}, &info)
 
 	return info, err
 }
@@ -484,21 +1140,24 @@ func (bot *BotAPI) GetUpdatesChan(config UpdateConfig) (UpdatesChannel, error) {
 	ch := make(chan Update, bot.Buffer)
 
 	go func() {
+		retries := 0
+
 		for {
 			select {
 			case <-bot.shutdownChannel:
 				return
 			default:
 			}
-			
-			updates, err := bot.GetUpdates(config)
+
+			updates, err := bot.getUpdates(WithRetryCount(context.Background(), retries), config)
 			if err != nil {
-				log.Println(err)
-				log.Println("Failed to get updates, retrying in 3 seconds...")
+				bot.logEvent(LogLevelError, "failed to get updates, retrying in 3 seconds", "error", err)
 				time.Sleep(time.Second * 3)
 
+				retries++
 				continue
 			}
+			retries = 0
 
 			for _, update := range updates {
 				if update.UpdateID >= config.Offset {
@@ -515,7 +1174,7 @@ func (bot *BotAPI) GetUpdatesChan(config UpdateConfig) (UpdatesChannel, error) {
 // StopReceivingUpdates stops the go routine which receives updates
 func (bot *BotAPI) StopReceivingUpdates() {
 	if bot.Debug {
-		log.Println("Stopping the update receiver routine...")
+		bot.logEvent(LogLevelDebug, "stopping the update receiver routine")
 	}
 	close(bot.shutdownChannel)
 }
@@ -529,7 +1188,10 @@ func (bot *BotAPI) ListenForWebhook(pattern string) UpdatesChannel {
 		r.Body.Close()
 
 		var update Update
-		json.Unmarshal(bytes, &update)
+		if err := json.Unmarshal(bytes, &update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
 		ch <- update
 	})
@@ -541,100 +1203,30 @@ func (bot *BotAPI) ListenForWebhook(pattern string) UpdatesChannel {
 //
 // Note that you must respond to an inline query within 30 seconds.
 func (bot *BotAPI) AnswerInlineQuery(config InlineConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	v.Add("inline_query_id", config.InlineQueryID)
-	v.Add("cache_time", strconv.Itoa(config.CacheTime))
-	v.Add("is_personal", strconv.FormatBool(config.IsPersonal))
-	v.Add("next_offset", config.NextOffset)
-	data, err := json.Marshal(config.Results)
-	if err != nil {
-		return APIResponse{}, err
-	}
-	v.Add("results", string(data))
-	v.Add("switch_pm_text", config.SwitchPMText)
-	v.Add("switch_pm_parameter", config.SwitchPMParameter)
-
-	bot.debugLog("answerInlineQuery", v, nil)
-
-	return bot.MakeRequest("answerInlineQuery", v)
+	return bot.Request(config)
 }
 
 // AnswerCallbackQuery sends a response to an inline query callback.
 func (bot *BotAPI) AnswerCallbackQuery(config CallbackConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	v.Add("callback_query_id", config.CallbackQueryID)
-	if config.Text != "" {
-		v.Add("text", config.Text)
-	}
-	v.Add("show_alert", strconv.FormatBool(config.ShowAlert))
-	if config.URL != "" {
-		v.Add("url", config.URL)
-	}
-	v.Add("cache_time", strconv.Itoa(config.CacheTime))
-
-	bot.debugLog("answerCallbackQuery", v, nil)
-
-	return bot.MakeRequest("answerCallbackQuery", v)
+	return bot.Request(config)
 }
 
 // KickChatMember kicks a user from a chat. Note that this only will work
 // in supergroups, and requires the bot to be an admin. Also note they
 // will be unable to rejoin until they are unbanned.
 func (bot *BotAPI) KickChatMember(config KickChatMemberConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-	v.Add("user_id", strconv.Itoa(config.UserID))
-
-	if config.UntilDate != 0 {
-		v.Add("until_date", strconv.FormatInt(config.UntilDate, 10))
-	}
-
-	bot.debugLog("kickChatMember", v, nil)
-
-	return bot.MakeRequest("kickChatMember", v)
+	return bot.Request(config)
 }
 
 // LeaveChat makes the bot leave the chat.
 func (bot *BotAPI) LeaveChat(config ChatConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-
-	bot.debugLog("leaveChat", v, nil)
-
-	return bot.MakeRequest("leaveChat", v)
+	return bot.Request(LeaveChatConfig{config})
 }
 
 // GetChat gets information about a chat.
 func (bot *BotAPI) GetChat(config ChatConfig) (Chat, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-
-	resp, err := bot.MakeRequest("getChat", v)
-	if err != nil {
-		return Chat{}, err
-	}
-
 	var chat Chat
-	err = json.Unmarshal(resp.Result, &chat)
-
-	bot.debugLog("getChat", v, chat)
+	err := bot.requestResult("getChat", config.chatValues(), &chat)
 
 	return chat, err
 }
@@ -644,70 +1236,24 @@ func (bot *BotAPI) GetChat(config ChatConfig) (Chat, error) {
 // If none have been appointed, only the creator will be returned.
 // Bots are not shown, even if they are an administrator.
 func (bot *BotAPI) GetChatAdministrators(config ChatConfig) ([]ChatMember, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-
-	resp, err := bot.MakeRequest("getChatAdministrators", v)
-	if err != nil {
-		return []ChatMember{}, err
-	}
-
 	var members []ChatMember
-	err = json.Unmarshal(resp.Result, &members)
-
-	bot.debugLog("getChatAdministrators", v, members)
+	err := bot.requestResult("getChatAdministrators", config.chatValues(), &members)
 
 	return members, err
 }
 
 // GetChatMembersCount gets the number of users in a chat.
 func (bot *BotAPI) GetChatMembersCount(config ChatConfig) (int, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-
-	resp, err := bot.MakeRequest("getChatMembersCount", v)
-	if err != nil {
-		return -1, err
-	}
-
 	var count int
-	err = json.Unmarshal(resp.Result, &count)
-
-	bot.debugLog("getChatMembersCount", v, count)
+	err := bot.requestResult("getChatMembersCount", config.chatValues(), &count)
 
 	return count, err
 }
 
 // GetChatMember gets a specific chat member.
 func (bot *BotAPI) GetChatMember(config ChatConfigWithUser) (ChatMember, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-	v.Add("user_id", strconv.Itoa(config.UserID))
-
-	resp, err := bot.MakeRequest("getChatMember", v)
-	if err != nil {
-		return ChatMember{}, err
-	}
-
 	var member ChatMember
-	err = json.Unmarshal(resp.Result, &member)
-
-	bot.debugLog("getChatMember", v, member)
+	err := bot.RequestResult(config, &member)
 
 	return member, err
 }
@@ -715,254 +1261,79 @@ func (bot *BotAPI) GetChatMember(config ChatConfigWithUser) (ChatMember, error)
 // UnbanChatMember unbans a user from a chat. Note that this only will work
 // in supergroups and channels, and requires the bot to be an admin.
 func (bot *BotAPI) UnbanChatMember(config ChatMemberConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername != "" {
-		v.Add("chat_id", config.SuperGroupUsername)
-	} else if config.ChannelUsername != "" {
-		v.Add("chat_id", config.ChannelUsername)
-	} else {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	}
-	v.Add("user_id", strconv.Itoa(config.UserID))
-
-	bot.debugLog("unbanChatMember", v, nil)
-
-	return bot.MakeRequest("unbanChatMember", v)
+	return bot.Request(UnbanChatMemberConfig{config})
 }
 
 // RestrictChatMember to restrict a user in a supergroup. The bot must be an
-//administrator in the supergroup for this to work and must have the
-//appropriate admin rights. Pass True for all boolean parameters to lift
-//restrictions from a user. Returns True on success.
+// administrator in the supergroup for this to work and must have the
+// appropriate admin rights. Pass True for all boolean parameters to lift
+// restrictions from a user. Returns True on success.
 func (bot *BotAPI) RestrictChatMember(config RestrictChatMemberConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername != "" {
-		v.Add("chat_id", config.SuperGroupUsername)
-	} else if config.ChannelUsername != "" {
-		v.Add("chat_id", config.ChannelUsername)
-	} else {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	}
-	v.Add("user_id", strconv.Itoa(config.UserID))
-
-	if config.CanSendMessages != nil {
-		v.Add("can_send_messages", strconv.FormatBool(*config.CanSendMessages))
-	}
-	if config.CanSendMediaMessages != nil {
-		v.Add("can_send_media_messages", strconv.FormatBool(*config.CanSendMediaMessages))
-	}
-	if config.CanSendOtherMessages != nil {
-		v.Add("can_send_other_messages", strconv.FormatBool(*config.CanSendOtherMessages))
-	}
-	if config.CanAddWebPagePreviews != nil {
-		v.Add("can_add_web_page_previews", strconv.FormatBool(*config.CanAddWebPagePreviews))
-	}
-	if config.UntilDate != 0 {
-		v.Add("until_date", strconv.FormatInt(config.UntilDate, 10))
-	}
-
-	bot.debugLog("restrictChatMember", v, nil)
-
-	return bot.MakeRequest("restrictChatMember", v)
+	return bot.Request(config)
 }
 
 // PromoteChatMember add admin rights to user
 func (bot *BotAPI) PromoteChatMember(config PromoteChatMemberConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername != "" {
-		v.Add("chat_id", config.SuperGroupUsername)
-	} else if config.ChannelUsername != "" {
-		v.Add("chat_id", config.ChannelUsername)
-	} else {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	}
-	v.Add("user_id", strconv.Itoa(config.UserID))
-
-	if config.CanChangeInfo != nil {
-		v.Add("can_change_info", strconv.FormatBool(*config.CanChangeInfo))
-	}
-	if config.CanPostMessages != nil {
-		v.Add("can_post_messages", strconv.FormatBool(*config.CanPostMessages))
-	}
-	if config.CanEditMessages != nil {
-		v.Add("can_edit_messages", strconv.FormatBool(*config.CanEditMessages))
-	}
-	if config.CanDeleteMessages != nil {
-		v.Add("can_delete_messages", strconv.FormatBool(*config.CanDeleteMessages))
-	}
-	if config.CanInviteUsers != nil {
-		v.Add("can_invite_users", strconv.FormatBool(*config.CanInviteUsers))
-	}
-	if config.CanRestrictMembers != nil {
-		v.Add("can_restrict_members", strconv.FormatBool(*config.CanRestrictMembers))
-	}
-	if config.CanPinMessages != nil {
-		v.Add("can_pin_messages", strconv.FormatBool(*config.CanPinMessages))
-	}
-	if config.CanPromoteMembers != nil {
-		v.Add("can_promote_members", strconv.FormatBool(*config.CanPromoteMembers))
-	}
-
-	bot.debugLog("promoteChatMember", v, nil)
-
-	return bot.MakeRequest("promoteChatMember", v)
+	return bot.Request(config)
 }
 
 // GetGameHighScores allows you to get the high scores for a game.
 func (bot *BotAPI) GetGameHighScores(config GetGameHighScoresConfig) ([]GameHighScore, error) {
-	v, _ := config.values()
-
-	resp, err := bot.MakeRequest(config.method(), v)
-	if err != nil {
-		return []GameHighScore{}, err
-	}
-
 	var highScores []GameHighScore
-	err = json.Unmarshal(resp.Result, &highScores)
+	err := bot.RequestResult(config, &highScores)
 
 	return highScores, err
 }
 
 // AnswerShippingQuery allows you to reply to Update with shipping_query parameter.
 func (bot *BotAPI) AnswerShippingQuery(config ShippingConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	v.Add("shipping_query_id", config.ShippingQueryID)
-	v.Add("ok", strconv.FormatBool(config.OK))
-	if config.OK == true {
-		data, err := json.Marshal(config.ShippingOptions)
-		if err != nil {
-			return APIResponse{}, err
-		}
-		v.Add("shipping_options", string(data))
-	} else {
-		v.Add("error_message", config.ErrorMessage)
-	}
-
-	bot.debugLog("answerShippingQuery", v, nil)
-
-	return bot.MakeRequest("answerShippingQuery", v)
+	return bot.Request(config)
 }
 
 // AnswerPreCheckoutQuery allows you to reply to Update with pre_checkout_query.
 func (bot *BotAPI) AnswerPreCheckoutQuery(config PreCheckoutConfig) (APIResponse, error) {
-	v := url.Values{}
-
-	v.Add("pre_checkout_query_id", config.PreCheckoutQueryID)
-	v.Add("ok", strconv.FormatBool(config.OK))
-	if config.OK != true {
-		v.Add("error", config.ErrorMessage)
-	}
-
-	bot.debugLog("answerPreCheckoutQuery", v, nil)
-
-	return bot.MakeRequest("answerPreCheckoutQuery", v)
+	return bot.Request(config)
 }
 
 // DeleteMessage deletes a message in a chat
 func (bot *BotAPI) DeleteMessage(config DeleteMessageConfig) (APIResponse, error) {
-	v, err := config.values()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	bot.debugLog(config.method(), v, nil)
-
-	return bot.MakeRequest(config.method(), v)
+	return bot.Request(config)
 }
 
 // GetInviteLink get InviteLink for a chat
 func (bot *BotAPI) GetInviteLink(config ChatConfig) (string, error) {
-	v := url.Values{}
-
-	if config.SuperGroupUsername == "" {
-		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
-	} else {
-		v.Add("chat_id", config.SuperGroupUsername)
-	}
-
-	resp, err := bot.MakeRequest("exportChatInviteLink", v)
-	if err != nil {
-		return "", err
-	}
-
 	var inviteLink string
-	err = json.Unmarshal(resp.Result, &inviteLink)
+	err := bot.requestResult("exportChatInviteLink", config.chatValues(), &inviteLink)
 
 	return inviteLink, err
 }
 
 // PinChatMessage pin message in supergroup
 func (bot *BotAPI) PinChatMessage(config PinChatMessageConfig) (APIResponse, error) {
-	v, err := config.values()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	bot.debugLog(config.method(), v, nil)
-
-	return bot.MakeRequest(config.method(), v)
+	return bot.Request(config)
 }
 
 // UnpinChatMessage unpin message in supergroup
 func (bot *BotAPI) UnpinChatMessage(config UnpinChatMessageConfig) (APIResponse, error) {
-	v, err := config.values()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	bot.debugLog(config.method(), v, nil)
-
-	return bot.MakeRequest(config.method(), v)
+	return bot.Request(config)
 }
 
 // SetChatTitle change title of chat.
 func (bot *BotAPI) SetChatTitle(config SetChatTitleConfig) (APIResponse, error) {
-	v, err := config.values()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	bot.debugLog(config.method(), v, nil)
-
-	return bot.MakeRequest(config.method(), v)
+	return bot.Request(config)
 }
 
 // SetChatDescription change description of chat.
 func (bot *BotAPI) SetChatDescription(config SetChatDescriptionConfig) (APIResponse, error) {
-	v, err := config.values()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	bot.debugLog(config.method(), v, nil)
-
-	return bot.MakeRequest(config.method(), v)
+	return bot.Request(config)
 }
 
 // SetChatPhoto change photo of chat.
 func (bot *BotAPI) SetChatPhoto(config SetChatPhotoConfig) (APIResponse, error) {
-	params, err := config.params()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	file := config.getFile()
-
-	return bot.UploadFile(config.method(), params, config.name(), file)
+	return bot.Request(config)
 }
 
 // DeleteChatPhoto delete photo of chat.
 func (bot *BotAPI) DeleteChatPhoto(config DeleteChatPhotoConfig) (APIResponse, error) {
-	v, err := config.values()
-	if err != nil {
-		return APIResponse{}, err
-	}
-
-	bot.debugLog(config.method(), v, nil)
-
-	return bot.MakeRequest(config.method(), v)
+	return bot.Request(config)
 }
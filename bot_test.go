@@ -1,10 +1,15 @@
 package tgbotapi_test
 
 import (
+	"context"
+	"encoding/json"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"path"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,6 +42,36 @@ func getBot(t *testing.T) (*tgbotapi.BotAPI, error) {
 	return bot, err
 }
 
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// getMockBot returns a bot which does not access the network. Every request
+// is passed to handler, which returns the result of the API method.
+func getMockBot(t *testing.T, handler func(method string, req *http.Request) interface{}) *tgbotapi.BotAPI {
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			result, err := json.Marshal(handler(path.Base(req.URL.Path), req))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body := `{"ok":true,"result":` + string(result) + `}`
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	return &tgbotapi.BotAPI{Token: TestToken, Client: client}
+}
+
 func TestNewBotAPI_notoken(t *testing.T) {
 	_, err := tgbotapi.NewBotAPI("")
 
@@ -530,6 +565,680 @@ func TestSendWithMediaGroup(t *testing.T) {
 	}
 }
 
+func TestSendMediaGroupWithUploads(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if method != "sendMediaGroup" {
+			t.Error(method)
+		}
+
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+
+		var media []map[string]interface{}
+		if err := json.Unmarshal([]byte(req.FormValue("media")), &media); err != nil {
+			t.Fatal(err)
+		}
+
+		if media[0]["media"] != "attach://file-0" ||
+			media[1]["media"] != "https://i.imgur.com/J5qweNZ.jpg" ||
+			media[2]["media"] != "attach://file-2" {
+			t.Error(media)
+		}
+
+		if len(req.MultipartForm.File["file-0"]) != 1 || len(req.MultipartForm.File["file-2"]) != 1 {
+			t.Error(req.MultipartForm.File)
+		}
+
+		return []tgbotapi.Message{{MessageID: 1}, {MessageID: 2}, {MessageID: 3}}
+	})
+
+	data, _ := ioutil.ReadFile("tests/image.jpg")
+
+	cfg := tgbotapi.NewMediaGroup(ChatID, []interface{}{
+		tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: "image.jpg", Bytes: data}),
+		tgbotapi.NewInputMediaPhoto("https://i.imgur.com/J5qweNZ.jpg"),
+		tgbotapi.NewInputMediaVideo(tgbotapi.FilePath("tests/video.mp4")),
+	})
+
+	messages, err := bot.SendMediaGroup(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 3 || messages[2].MessageID != 3 {
+		t.Error(messages)
+	}
+}
+
+func TestSendMediaGroupWithPointerUploads(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+
+		var media []map[string]interface{}
+		if err := json.Unmarshal([]byte(req.FormValue("media")), &media); err != nil {
+			t.Fatal(err)
+		}
+
+		if media[0]["media"] != "attach://file-0" || media[1]["media"] != "attach://file-1" {
+			t.Error(media)
+		}
+
+		if len(req.MultipartForm.File["file-0"]) != 1 || len(req.MultipartForm.File["file-1"]) != 1 {
+			t.Error(req.MultipartForm.File)
+		}
+
+		return []tgbotapi.Message{{MessageID: 1}, {MessageID: 2}}
+	})
+
+	photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FilePath("tests/image.jpg"))
+	video := tgbotapi.NewInputMediaVideo(tgbotapi.FilePath("tests/video.mp4"))
+
+	if _, err := bot.SendMediaGroup(tgbotapi.NewMediaGroup(ChatID, []interface{}{&photo, &video})); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := photo.Media.(tgbotapi.FilePath); !ok {
+		t.Error("the media of the config must not be replaced")
+	}
+}
+
+func TestSendMediaGroupWithVideoThumb(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+
+		var media []map[string]interface{}
+		if err := json.Unmarshal([]byte(req.FormValue("media")), &media); err != nil {
+			t.Fatal(err)
+		}
+
+		if media[1]["media"] != "attach://file-1" || media[1]["thumb"] != "attach://file-1-thumb" {
+			t.Error(media)
+		}
+		if _, ok := media[0]["thumb"]; ok {
+			t.Error(media)
+		}
+
+		if len(req.MultipartForm.File["file-1"]) != 1 || len(req.MultipartForm.File["file-1-thumb"]) != 1 {
+			t.Error(req.MultipartForm.File)
+		}
+
+		return []tgbotapi.Message{{MessageID: 1}, {MessageID: 2}}
+	})
+
+	video := tgbotapi.NewInputMediaVideo(tgbotapi.FilePath("tests/video.mp4"))
+	video.Thumb = tgbotapi.FilePath("tests/image.jpg")
+
+	group := tgbotapi.NewMediaGroup(ChatID, []interface{}{
+		tgbotapi.NewInputMediaVideo(tgbotapi.FilePath("tests/video.mp4")),
+		&video,
+	})
+
+	if _, err := bot.SendMediaGroup(group); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendWithThumb(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(req.MultipartForm.File["video"]) != 1 || len(req.MultipartForm.File["thumb"]) != 1 {
+			t.Error(req.MultipartForm.File)
+		}
+
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	cfg := tgbotapi.NewVideoUpload(ChatID, "tests/video.mp4")
+	cfg.Thumb = "tests/image.jpg"
+
+	if _, err := bot.Send(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendExistingWithThumb(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+
+		if req.FormValue("document") != ExistingDocumentFileID {
+			t.Errorf("expected the file ID as a field, got %q", req.FormValue("document"))
+		}
+
+		if len(req.MultipartForm.File["document"]) != 0 || len(req.MultipartForm.File["thumb"]) != 1 {
+			t.Error(req.MultipartForm.File)
+		}
+
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	cfg := tgbotapi.NewDocumentShare(ChatID, ExistingDocumentFileID)
+	cfg.Thumb = tgbotapi.FileBytes{Name: "thumb.jpg", Bytes: []byte("thumb")}
+
+	if _, err := bot.Send(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return map[string]interface{}{"id": "not a number"}
+	})
+
+	_, err := bot.GetMe()
+
+	decodeErr, ok := err.(tgbotapi.DecodeError)
+	if !ok {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+
+	if decodeErr.Method != "getMe" || !strings.Contains(decodeErr.Payload, "not a number") {
+		t.Error(decodeErr)
+	}
+
+	if decodeErr.Unwrap() != decodeErr.Err || decodeErr.Err == nil {
+		t.Error(decodeErr.Unwrap())
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return map[string]interface{}{"id": 1, "is_bot": true, "new_field": true}
+	})
+
+	if _, err := bot.GetMe(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &recordingLogger{}
+	bot.Logger = logger
+	bot.StrictDecoding = true
+
+	user, err := bot.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 1 {
+		t.Error(user)
+	}
+
+	if out := logger.out.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "new_field") {
+		t.Errorf("expected an unknown field warning, got %q", out)
+	}
+}
+
+func TestEditInlineMessage(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return true
+	})
+
+	edit := tgbotapi.EditMessageTextConfig{
+		BaseEdit: tgbotapi.BaseEdit{InlineMessageID: "inline"},
+		Text:     "edited",
+	}
+
+	if _, err := bot.Send(edit); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if method != "deleteMessage" {
+			t.Error(method)
+		}
+
+		return true
+	})
+
+	resp, err := bot.Request(tgbotapi.DeleteMessageConfig{ChatID: ChatID, MessageID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Ok || string(resp.Result) != "true" {
+		t.Error(resp)
+	}
+}
+
+func TestRequestUploadsFiles(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if _, _, err := req.FormFile("photo"); err != nil {
+			t.Error(err)
+		}
+
+		return true
+	})
+
+	if _, err := bot.Request(tgbotapi.NewSetChatPhotoUpload(ChatID, "tests/image.jpg")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequestResult(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return []tgbotapi.GameHighScore{{Position: 1, Score: 10}}
+	})
+
+	var scores []tgbotapi.GameHighScore
+	if err := bot.RequestResult(tgbotapi.GetGameHighScoresConfig{UserID: 1}, &scores); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scores) != 1 || scores[0].Score != 10 {
+		t.Error(scores)
+	}
+}
+
+func TestRequestResultLogsOnce(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return []tgbotapi.GameHighScore{{Position: 1, Score: 10}}
+	})
+	logger := &recordingLogger{}
+	bot.Logger = logger
+	bot.Debug = true
+
+	var scores []tgbotapi.GameHighScore
+	if err := bot.RequestResult(tgbotapi.GetGameHighScoresConfig{UserID: 1}, &scores); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests := strings.Count(logger.out.String(), `msg="request"`); requests != 1 {
+		t.Errorf("expected the request to be logged once, got %q", logger.out.String())
+	}
+}
+
+func TestLeaveChatAndUnbanChatMember(t *testing.T) {
+	var methods []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		methods = append(methods, method+" "+req.FormValue("chat_id")+" "+req.FormValue("user_id"))
+
+		return true
+	})
+
+	if _, err := bot.LeaveChat(tgbotapi.ChatConfig{ChatID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.UnbanChatMember(tgbotapi.ChatMemberConfig{ChatID: 1, UserID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(methods, ",") != "leaveChat 1 ,unbanChatMember 1 2" {
+		t.Error(methods)
+	}
+}
+
+func TestWebhookRequests(t *testing.T) {
+	var methods []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+			if _, _, err := req.FormFile("certificate"); err != nil {
+				t.Error(err)
+			}
+		}
+		req.ParseForm()
+		methods = append(methods, method+" "+req.FormValue("url"))
+
+		if method == "getWebhookInfo" {
+			return tgbotapi.WebhookInfo{URL: "https://example.com", PendingUpdateCount: 2}
+		}
+
+		return true
+	})
+
+	if _, err := bot.SetWebhook(tgbotapi.NewWebhook("https://example.com")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.SetWebhook(tgbotapi.NewWebhookWithCert("https://example.com", "tests/cert.pem")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.RemoveWebhook(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := bot.GetWebhookInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.URL != "https://example.com" || info.PendingUpdateCount != 2 {
+		t.Error(info)
+	}
+
+	expected := "setWebhook https://example.com,setWebhook https://example.com,setWebhook ,getWebhookInfo "
+	if strings.Join(methods, ",") != expected {
+		t.Error(methods)
+	}
+}
+
+// getDownloadBot returns a bot which serves a file with content, while
+// reporting size as its size.
+func getDownloadBot(t *testing.T, content string, size int) *tgbotapi.BotAPI {
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := content
+			if !strings.HasPrefix(req.URL.Path, "/file/") {
+				file, _ := json.Marshal(tgbotapi.File{FileID: "id", FileSize: size, FilePath: "documents/file.txt"})
+				body = `{"ok":true,"result":` + string(file) + `}`
+			}
+
+			return &http.Response{
+				StatusCode:    http.StatusOK,
+				ContentLength: -1,
+				Body:          ioutil.NopCloser(strings.NewReader(body)),
+				Request:       req,
+			}, nil
+		}),
+	}
+
+	return &tgbotapi.BotAPI{Token: TestToken, Client: client}
+}
+
+func TestDownloadFile(t *testing.T) {
+	bot := getDownloadBot(t, "content", 7)
+
+	body, file, err := bot.DownloadFile("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "content" || file.FilePath != "documents/file.txt" {
+		t.Error(string(data), file)
+	}
+}
+
+func TestDownloadFileTooBig(t *testing.T) {
+	bot := getDownloadBot(t, "content", tgbotapi.MaxDownloadFileSize+1)
+
+	_, _, err := bot.DownloadFile("id")
+	if _, ok := err.(tgbotapi.FileTooBigError); !ok {
+		t.Fatalf("expected FileTooBigError, got %v", err)
+	}
+
+	// Telegram may not report the size, so it is enforced while reading.
+	bot = getDownloadBot(t, "content", 0)
+	bot.MaxDownloadSize = 4
+
+	body, _, err := bot.DownloadFile("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+
+	if _, err := ioutil.ReadAll(body); err == nil {
+		t.Fatal("expected an error")
+	} else if tooBig, ok := err.(tgbotapi.FileTooBigError); !ok || tooBig.Limit != 4 {
+		t.Errorf("expected FileTooBigError, got %v", err)
+	}
+}
+
+func TestDownloadFileSizeMismatch(t *testing.T) {
+	bot := getDownloadBot(t, "cont", 7)
+
+	body, _, err := bot.DownloadFile("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+
+	if _, err := ioutil.ReadAll(body); err == nil {
+		t.Fatal("expected an error")
+	} else if _, ok := err.(tgbotapi.FileSizeMismatchError); !ok {
+		t.Errorf("expected FileSizeMismatchError, got %v", err)
+	}
+}
+
+func TestDownloadFileContextCancelsLookup(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasSuffix(req.URL.Path, "/getFile") {
+				t.Errorf("unexpected request to %s", req.URL.Path)
+			}
+
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}),
+	}
+	bot := &tgbotapi.BotAPI{Token: TestToken, Client: client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := bot.DownloadFileContext(ctx, "id"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestDownloadFileTo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tgbotapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	target := path.Join(dir, "file.txt")
+
+	bot := getDownloadBot(t, "cont", 7)
+	if _, err := bot.DownloadFileTo("id", target); err == nil {
+		t.Error("expected an error")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("a failed download must not create the file")
+	}
+
+	bot = getDownloadBot(t, "content", 7)
+	if _, err := bot.DownloadFileTo("id", target); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := ioutil.ReadFile(target)
+	if string(data) != "content" {
+		t.Error(string(data))
+	}
+}
+
+func TestUploadFileStreamsUnknownSizeReader(t *testing.T) {
+	content := strings.Repeat("video", 1<<16)
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if req.ContentLength > 0 {
+			t.Errorf("expected a chunked request, got length %d", req.ContentLength)
+		}
+
+		file, header, err := req.FormFile("video")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		data, _ := ioutil.ReadAll(file)
+		if header.Filename != "video.mp4" || string(data) != content {
+			t.Error("uploaded file does not match")
+		}
+
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	msg := tgbotapi.NewVideoUpload(ChatID, tgbotapi.FileReader{
+		Name:   "video.mp4",
+		Reader: strings.NewReader(content),
+		Size:   -1,
+	})
+
+	if _, err := bot.Send(msg); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUploadFileSetsContentLength(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.ContentLength != int64(len(body)) {
+			t.Errorf("expected length %d, got %d", len(body), req.ContentLength)
+		}
+
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	data, _ := ioutil.ReadFile("tests/image.jpg")
+
+	photo := tgbotapi.NewPhotoUpload(ChatID, "tests/image.jpg")
+	photo.Caption = "caption"
+
+	document := tgbotapi.NewDocumentUpload(ChatID, tgbotapi.FileReader{
+		Name:   "image.jpg",
+		Reader: strings.NewReader(string(data)),
+		Size:   int64(len(data)),
+	})
+
+	group := tgbotapi.NewMediaGroup(ChatID, []interface{}{
+		tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: "image.jpg", Bytes: data}),
+		tgbotapi.NewInputMediaPhoto(tgbotapi.FilePath("tests/image.jpg")),
+	})
+
+	for _, c := range []tgbotapi.Chattable{photo, document, group} {
+		if _, err := bot.Request(c); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestUploadProgress(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		ioutil.ReadAll(req.Body)
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	data, _ := ioutil.ReadFile("tests/image.jpg")
+
+	var sent, total int64
+	msg := tgbotapi.NewPhotoUpload(ChatID, tgbotapi.FileBytes{Name: "image.jpg", Bytes: data})
+	msg.UploadProgress = func(s, t int64) {
+		sent, total = s, t
+	}
+
+	if _, err := bot.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent != int64(len(data)) || total != int64(len(data)) {
+		t.Errorf("sent %d of %d bytes, expected %d", sent, total, len(data))
+	}
+}
+
+func TestUploadCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			defer req.Body.Close()
+
+			buf := make([]byte, 1024)
+			if _, err := req.Body.Read(buf); err != nil {
+				return nil, err
+			}
+
+			cancel()
+
+			_, err := ioutil.ReadAll(req.Body)
+			if err == nil {
+				t.Error("expected upload to stop")
+			}
+
+			return nil, err
+		}),
+	}
+	bot := &tgbotapi.BotAPI{Token: TestToken, Client: client}
+
+	msg := tgbotapi.NewDocumentUpload(ChatID, tgbotapi.FileReader{
+		Name:   "large.bin",
+		Reader: strings.NewReader(strings.Repeat("a", 1<<20)),
+		Size:   -1,
+	})
+
+	if _, err := bot.SendContext(ctx, msg); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestStartChatAction(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		mu.Lock()
+		actions = append(actions, method+" "+req.FormValue("action"))
+		mu.Unlock()
+
+		return true
+	})
+	bot.ChatActionInterval = 10 * time.Millisecond
+
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(actions)
+	}
+
+	stop := bot.StartChatAction(context.Background(), tgbotapi.NewChatAction(ChatID, tgbotapi.ChatUploadVideo))
+
+	deadline := time.Now().Add(time.Second)
+	for count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	stop()
+	sent := count()
+	if sent < 3 {
+		t.Fatalf("expected the action to be repeated, sent %d", sent)
+	}
+	for _, action := range actions {
+		if action != "sendChatAction upload_video" {
+			t.Error(action)
+		}
+	}
+
+	time.Sleep(5 * bot.ChatActionInterval)
+	if count() != sent {
+		t.Errorf("sent %d actions after stop", count()-sent)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop = bot.StartChatAction(ctx, tgbotapi.NewChatAction(ChatID, tgbotapi.ChatTyping))
+	cancel()
+	stop()
+
+	sent = count()
+	time.Sleep(5 * bot.ChatActionInterval)
+	if count() != sent {
+		t.Errorf("sent %d actions after the context was done", count()-sent)
+	}
+}
+
 func ExampleNewBotAPI() {
 	bot, err := tgbotapi.NewBotAPI("MyAwesomeBotToken")
 	if err != nil {
@@ -618,7 +1327,7 @@ func ExampleAnswerInlineQuery() {
 			InlineQueryID: update.InlineQuery.ID,
 			IsPersonal:    true,
 			CacheTime:     0,
-			Results:       []interface{}{article},
+			Results:       []tgbotapi.InlineQueryResult{article},
 		}
 
 		if _, err := bot.AnswerInlineQuery(inlineConf); err != nil {
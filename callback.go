@@ -0,0 +1,479 @@
+package tgbotapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxCallbackDataLength is the largest callback_data Telegram accepts in
+// bytes.
+const MaxCallbackDataLength = 64
+
+// DefaultCallbackSignatureSize is the number of bytes of the HMAC kept in
+// callback data signed by a CallbackCodec.
+const DefaultCallbackSignatureSize = 6
+
+// callbackSeparator separates the prefix, fields and signature of encoded
+// callback data.
+const callbackSeparator = "|"
+
+var callbackEscaper = strings.NewReplacer("%", "%25", callbackSeparator, "%7C")
+var callbackUnescaper = strings.NewReplacer("%7C", callbackSeparator, "%25", "%")
+
+// CallbackCodec encodes structs into compact callback data and back.
+//
+// Callback data consists of a prefix naming the action, followed by the
+// exported fields of the struct in order, such as "vote|42|1". Fields may
+// be strings, booleans, integers or floats.
+//
+// If Key is set, the data is signed with an HMAC so users can not forge
+// it.
+//
+// If Store is set, the encoded data is kept in the Store instead and
+// callback_data only contains a short random key, so the data may be
+// longer than MaxCallbackDataLength.
+type CallbackCodec struct {
+	Key []byte
+	// SignatureSize is the number of bytes of the HMAC to keep,
+	// DefaultCallbackSignatureSize if zero.
+	SignatureSize int
+
+	Store CallbackStore
+	// TTL is how long data is kept in the Store, DefaultCallbackTTL if
+	// zero.
+	TTL time.Duration
+}
+
+// NewCallbackCodec creates a new CallbackCodec. key may be nil to not sign
+// the data.
+func NewCallbackCodec(key []byte) *CallbackCodec {
+	return &CallbackCodec{
+		Key: key,
+	}
+}
+
+// Encode encodes data as callback data for the action prefix. data is a
+// struct or a pointer to one, or nil for actions without data.
+//
+// It fails if the result is longer than MaxCallbackDataLength, unless the
+// codec has a Store.
+func (c *CallbackCodec) Encode(prefix string, data interface{}) (string, error) {
+	if prefix == "" || strings.Contains(prefix, callbackSeparator) || strings.HasPrefix(prefix, callbackStoreMarker) {
+		return "", errors.New(ErrCallbackPrefix)
+	}
+
+	parts := []string{prefix}
+
+	if data != nil {
+		v := reflect.Indirect(reflect.ValueOf(data))
+		if v.Kind() != reflect.Struct {
+			return "", errors.New(ErrCallbackDataType)
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+
+			field, err := encodeCallbackField(v.Field(i))
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, callbackEscaper.Replace(field))
+		}
+	}
+
+	encoded := strings.Join(parts, callbackSeparator)
+	if c.Store != nil {
+		return c.store(encoded)
+	}
+
+	if c.Key != nil {
+		encoded += callbackSeparator + c.sign(encoded)
+	}
+
+	if len(encoded) > MaxCallbackDataLength {
+		return "", errors.New(ErrCallbackDataTooLong)
+	}
+
+	return encoded, nil
+}
+
+// Decode verifies callbackData and decodes its fields into data, which
+// must be a pointer to a struct of the type it was encoded from, or nil to
+// only verify it. It returns the prefix of the data.
+//
+// Data whose payload expired from the Store fails with a
+// CallbackExpiredError.
+func (c *CallbackCodec) Decode(callbackData string, data interface{}) (string, error) {
+	parts, err := c.resolve(callbackData)
+	if err != nil {
+		return "", err
+	}
+
+	return decodeCallbackParts(parts, data)
+}
+
+// decodeCallbackParts decodes the fields of resolved callback data into
+// data and returns its prefix.
+func decodeCallbackParts(parts []string, data interface{}) (string, error) {
+	if data == nil {
+		return parts[0], nil
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return "", errors.New(ErrCallbackDataType)
+	}
+	v = v.Elem()
+
+	fields := parts[1:]
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath != "" {
+			continue
+		}
+
+		if len(fields) == 0 {
+			return "", errors.New(ErrCallbackDataMalformed)
+		}
+
+		if err := decodeCallbackField(v.Field(i), callbackUnescaper.Replace(fields[0])); err != nil {
+			return "", err
+		}
+		fields = fields[1:]
+	}
+
+	if len(fields) != 0 {
+		return "", errors.New(ErrCallbackDataMalformed)
+	}
+
+	return parts[0], nil
+}
+
+// Prefix returns the prefix of callbackData without verifying it. It is
+// empty for data kept in a Store.
+func (c *CallbackCodec) Prefix(callbackData string) string {
+	if c.Store != nil && strings.HasPrefix(callbackData, callbackStoreMarker) {
+		return ""
+	}
+
+	if i := strings.Index(callbackData, callbackSeparator); i >= 0 {
+		return callbackData[:i]
+	}
+
+	return callbackData
+}
+
+// Button creates an inline keyboard button with text, which sends data
+// encoded for the action prefix.
+func (c *CallbackCodec) Button(text, prefix string, data interface{}) (InlineKeyboardButton, error) {
+	encoded, err := c.Encode(prefix, data)
+	if err != nil {
+		return InlineKeyboardButton{}, err
+	}
+
+	return NewInlineKeyboardButtonData(text, encoded), nil
+}
+
+// resolve returns the prefix and fields of callbackData, after looking it
+// up in the Store or verifying its signature.
+func (c *CallbackCodec) resolve(callbackData string) ([]string, error) {
+	if c.Store == nil || !strings.HasPrefix(callbackData, callbackStoreMarker) {
+		return c.verify(callbackData)
+	}
+
+	key := strings.TrimPrefix(callbackData, callbackStoreMarker)
+
+	payload, ok, err := c.Store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, CallbackExpiredError{Key: key}
+	}
+
+	return strings.Split(payload, callbackSeparator), nil
+}
+
+// verify checks the signature of callbackData if the codec has a Key and
+// returns its prefix and fields.
+func (c *CallbackCodec) verify(callbackData string) ([]string, error) {
+	parts := strings.Split(callbackData, callbackSeparator)
+
+	if c.Key != nil {
+		if len(parts) < 2 {
+			return nil, errors.New(ErrCallbackSignature)
+		}
+
+		signed := strings.Join(parts[:len(parts)-1], callbackSeparator)
+		if !hmac.Equal([]byte(parts[len(parts)-1]), []byte(c.sign(signed))) {
+			return nil, errors.New(ErrCallbackSignature)
+		}
+
+		parts = parts[:len(parts)-1]
+	}
+
+	return parts, nil
+}
+
+// sign returns the truncated HMAC of data.
+func (c *CallbackCodec) sign(data string) string {
+	size := c.SignatureSize
+	if size <= 0 {
+		size = DefaultCallbackSignatureSize
+	}
+
+	mac := hmac.New(sha256.New, c.Key)
+	mac.Write([]byte(data))
+	sum := mac.Sum(nil)
+	if size < len(sum) {
+		sum = sum[:size]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(sum)
+}
+
+// encodeCallbackField formats a struct field of callback data.
+func encodeCallbackField(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return "1", nil
+		}
+		return "0", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 36), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 36), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	default:
+		return "", errors.New(ErrCallbackDataType)
+	}
+}
+
+// decodeCallbackField parses s into a struct field of callback data.
+func decodeCallbackField(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		v.SetBool(s == "1")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 36, v.Type().Bits())
+		if err != nil {
+			return errors.New(ErrCallbackDataMalformed)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 36, v.Type().Bits())
+		if err != nil {
+			return errors.New(ErrCallbackDataMalformed)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return errors.New(ErrCallbackDataMalformed)
+		}
+		v.SetFloat(f)
+	default:
+		return errors.New(ErrCallbackDataType)
+	}
+
+	return nil
+}
+
+// CallbackContext is passed to a CallbackHandler for a CallbackQuery.
+type CallbackContext struct {
+	Bot    *BotAPI
+	Query  *CallbackQuery
+	Prefix string
+
+	parts []string
+
+	mu       sync.Mutex
+	answered bool
+}
+
+// Decode decodes the callback data into data, which must be a pointer to
+// a struct of the type it was encoded from.
+func (c *CallbackContext) Decode(data interface{}) error {
+	_, err := decodeCallbackParts(c.parts, data)
+	return err
+}
+
+// Answer answers the CallbackQuery with text, which is shown as an alert
+// if showAlert is set. The router does not answer it again afterwards.
+func (c *CallbackContext) Answer(text string, showAlert bool) error {
+	config := NewCallback(c.Query.ID, text)
+	config.ShowAlert = showAlert
+
+	return c.AnswerWith(config)
+}
+
+// AnswerWith answers the CallbackQuery with config.
+func (c *CallbackContext) AnswerWith(config CallbackConfig) error {
+	c.mu.Lock()
+	c.answered = true
+	c.mu.Unlock()
+
+	_, err := c.Bot.Request(config)
+	return err
+}
+
+// isAnswered returns if the CallbackQuery was answered.
+func (c *CallbackContext) isAnswered() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.answered
+}
+
+// CallbackHandler handles a CallbackQuery routed by a CallbackRouter.
+type CallbackHandler func(c *CallbackContext) error
+
+// CallbackRouter dispatches CallbackQuery updates to handlers by the
+// prefix of their data, as encoded by its Codec.
+//
+// Telegram shows a progress indicator until a CallbackQuery is answered,
+// so the router answers it without text if the handler did not.
+type CallbackRouter struct {
+	Bot *BotAPI
+	// Codec decodes the callback data, an unsigned CallbackCodec if nil.
+	Codec *CallbackCodec
+
+	// OnError is called if data can not be verified, no handler exists
+	// for its prefix, the handler fails, or the CallbackQuery can not be
+	// answered. If it is nil, the error is logged by the Bot.
+	OnError func(c *CallbackContext, err error)
+
+	// ExpiredText is shown to users pressing a button whose data expired
+	// from the Store of the Codec, DefaultCallbackExpiredText if empty.
+	ExpiredText string
+
+	handlers map[string]CallbackHandler
+}
+
+// NewCallbackRouter creates a new CallbackRouter.
+func NewCallbackRouter(bot *BotAPI, codec *CallbackCodec) *CallbackRouter {
+	return &CallbackRouter{
+		Bot:      bot,
+		Codec:    codec,
+		handlers: make(map[string]CallbackHandler),
+	}
+}
+
+// Handle registers handler for callback data with prefix.
+func (r *CallbackRouter) Handle(prefix string, handler CallbackHandler) {
+	if r.handlers == nil {
+		r.handlers = make(map[string]CallbackHandler)
+	}
+
+	r.handlers[prefix] = handler
+}
+
+// HandleUpdate dispatches update if it is a CallbackQuery and returns if
+// it was.
+func (r *CallbackRouter) HandleUpdate(update Update) bool {
+	if update.CallbackQuery == nil {
+		return false
+	}
+
+	r.HandleCallbackQuery(update.CallbackQuery)
+
+	return true
+}
+
+// HandleCallbackQuery dispatches query to the handler for its prefix.
+func (r *CallbackRouter) HandleCallbackQuery(query *CallbackQuery) {
+	c := &CallbackContext{
+		Bot:   r.Bot,
+		Query: query,
+	}
+
+	err := r.dispatch(c)
+	if _, expired := err.(CallbackExpiredError); expired && !c.isAnswered() {
+		text := r.ExpiredText
+		if text == "" {
+			text = DefaultCallbackExpiredText
+		}
+
+		if answerErr := c.Answer(text, true); answerErr != nil {
+			r.handleError(c, answerErr)
+		}
+	}
+
+	if err != nil {
+		r.handleError(c, err)
+	}
+
+	if !c.isAnswered() {
+		if err := c.AnswerWith(NewCallback(query.ID, "")); err != nil {
+			r.handleError(c, err)
+		}
+	}
+}
+
+// dispatch resolves the data of c and calls the handler for its prefix.
+func (r *CallbackRouter) dispatch(c *CallbackContext) error {
+	parts, err := r.codec().resolve(c.Query.Data)
+	if err != nil {
+		return err
+	}
+
+	c.parts = parts
+	c.Prefix = parts[0]
+
+	handler, ok := r.handlers[c.Prefix]
+	if !ok {
+		return errors.New(ErrCallbackNoHandler)
+	}
+
+	return handler(c)
+}
+
+// codec returns the Codec of the router, or an unsigned CallbackCodec if
+// it has none.
+func (r *CallbackRouter) codec() *CallbackCodec {
+	if r.Codec == nil {
+		return &CallbackCodec{}
+	}
+
+	return r.Codec
+}
+
+// handleError passes err to OnError, or logs it if there is none.
+func (r *CallbackRouter) handleError(c *CallbackContext, err error) {
+	if r.OnError != nil {
+		r.OnError(c, err)
+		return
+	}
+
+	if r.Bot != nil {
+		r.Bot.logEvent(LogLevelError, "handling callback query failed",
+			"query", c.Query.ID, "data", c.Query.Data, "error", err)
+	}
+}
+
+// baseEdit returns a BaseEdit for the message the CallbackQuery came from.
+func (c *CallbackContext) baseEdit() BaseEdit {
+	if c.Query.Message == nil || c.Query.Message.Chat == nil {
+		return BaseEdit{InlineMessageID: c.Query.InlineMessageID}
+	}
+
+	return BaseEdit{
+		ChatID:    c.Query.Message.Chat.ID,
+		MessageID: c.Query.Message.MessageID,
+	}
+}
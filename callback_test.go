@@ -0,0 +1,377 @@
+package tgbotapi_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+type voteData struct {
+	PollID int64
+	Option int
+	Label  string
+	Undo   bool
+}
+
+func TestCallbackCodec(t *testing.T) {
+	for _, codec := range []*tgbotapi.CallbackCodec{
+		tgbotapi.NewCallbackCodec(nil),
+		tgbotapi.NewCallbackCodec([]byte("secret")),
+	} {
+		in := voteData{PollID: 1234567890, Option: -3, Label: "a|b%7C", Undo: true}
+
+		data, err := codec.Encode("vote", in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out voteData
+		prefix, err := codec.Decode(data, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if prefix != "vote" || out != in {
+			t.Errorf("%s decoded to %s %+v", data, prefix, out)
+		}
+	}
+}
+
+func TestCallbackCodecTooLong(t *testing.T) {
+	codec := tgbotapi.NewCallbackCodec(nil)
+
+	_, err := codec.Encode("vote", voteData{Label: strings.Repeat("a", tgbotapi.MaxCallbackDataLength)})
+	if err == nil || err.Error() != tgbotapi.ErrCallbackDataTooLong {
+		t.Errorf("expected %q, got %v", tgbotapi.ErrCallbackDataTooLong, err)
+	}
+}
+
+func TestCallbackCodecSignature(t *testing.T) {
+	codec := tgbotapi.NewCallbackCodec([]byte("secret"))
+
+	data, _ := codec.Encode("vote", voteData{Option: 1})
+	forged := strings.Replace(data, "vote|0|1|", "vote|0|2|", 1)
+
+	if _, err := codec.Decode(forged, &voteData{}); err == nil || err.Error() != tgbotapi.ErrCallbackSignature {
+		t.Errorf("expected %q, got %v", tgbotapi.ErrCallbackSignature, err)
+	}
+
+	other := tgbotapi.NewCallbackCodec([]byte("other"))
+	if _, err := other.Decode(data, &voteData{}); err == nil {
+		t.Error("data signed with another key was accepted")
+	}
+}
+
+func TestCallbackRouter(t *testing.T) {
+	var answers []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if method != "answerCallbackQuery" {
+			t.Error(method)
+		}
+
+		req.ParseForm()
+		answers = append(answers, req.FormValue("text"))
+
+		return true
+	})
+
+	codec := tgbotapi.NewCallbackCodec([]byte("secret"))
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+
+	var voted voteData
+	router.Handle("vote", func(c *tgbotapi.CallbackContext) error {
+		if err := c.Decode(&voted); err != nil {
+			return err
+		}
+
+		return c.Answer("Thanks!", false)
+	})
+	router.Handle("silent", func(c *tgbotapi.CallbackContext) error {
+		return nil
+	})
+
+	var errs []error
+	router.OnError = func(c *tgbotapi.CallbackContext, err error) {
+		errs = append(errs, err)
+	}
+
+	vote, _ := codec.Encode("vote", voteData{Option: 2})
+	silent, _ := codec.Encode("silent", nil)
+
+	for _, data := range []string{vote, silent, "unknown", "vote|0|2|0|forged"} {
+		handled := router.HandleUpdate(tgbotapi.Update{
+			CallbackQuery: &tgbotapi.CallbackQuery{ID: "query", Data: data},
+		})
+		if !handled {
+			t.Error("callback query was not handled")
+		}
+	}
+
+	if voted.Option != 2 {
+		t.Error(voted)
+	}
+
+	if len(answers) != 4 || answers[0] != "Thanks!" || answers[1] != "" {
+		t.Errorf("every query must be answered exactly once, got %q", answers)
+	}
+
+	if len(errs) != 2 {
+		t.Fatal(errs)
+	}
+	if errs[0].Error() != tgbotapi.ErrCallbackSignature || errs[1].Error() != tgbotapi.ErrCallbackSignature {
+		t.Error(errs)
+	}
+
+	if router.HandleUpdate(tgbotapi.Update{Message: &tgbotapi.Message{}}) {
+		t.Error("a message was handled")
+	}
+}
+
+func TestCallbackRouterZeroValue(t *testing.T) {
+	var answered []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		answered = append(answered, req.FormValue("callback_query_id"))
+
+		return true
+	})
+
+	router := &tgbotapi.CallbackRouter{Bot: bot}
+
+	called := false
+	router.Handle("silent", func(c *tgbotapi.CallbackContext) error {
+		called = true
+		return nil
+	})
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "query", Data: "silent"}})
+
+	if !called || len(answered) != 1 {
+		t.Error(called, answered)
+	}
+}
+
+func TestCallbackRouterAnswerError(t *testing.T) {
+	bot := &tgbotapi.BotAPI{
+		Token: TestToken,
+		Client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("network down")
+		})},
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, nil)
+	router.Handle("silent", func(c *tgbotapi.CallbackContext) error {
+		return nil
+	})
+
+	var errs []error
+	router.OnError = func(c *tgbotapi.CallbackContext, err error) {
+		errs = append(errs, err)
+	}
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "query", Data: "silent"}})
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "network down") {
+		t.Error(errs)
+	}
+}
+
+func TestCallbackRouterNoHandler(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return true
+	})
+
+	router := tgbotapi.NewCallbackRouter(bot, tgbotapi.NewCallbackCodec(nil))
+
+	var err error
+	router.OnError = func(c *tgbotapi.CallbackContext, e error) {
+		err = e
+	}
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "query", Data: "unknown"}})
+
+	if err == nil || err.Error() != tgbotapi.ErrCallbackNoHandler {
+		t.Error(err)
+	}
+}
+
+type largeData struct {
+	Query string
+	Page  int
+}
+
+func TestCallbackStore(t *testing.T) {
+	var answers []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		answers = append(answers, req.FormValue("text")+"|"+req.FormValue("show_alert"))
+
+		return true
+	})
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	codec.Store = tgbotapi.NewMemoryCallbackStore()
+	codec.TTL = time.Hour
+
+	in := largeData{Query: strings.Repeat("long search query ", 10), Page: 3}
+
+	button, err := codec.Button("Next", "search", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*button.CallbackData) > tgbotapi.MaxCallbackDataLength {
+		t.Errorf("callback data is too long: %q", *button.CallbackData)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+	router.OnError = func(c *tgbotapi.CallbackContext, err error) {}
+
+	var out largeData
+	router.Handle("search", func(c *tgbotapi.CallbackContext) error {
+		return c.Decode(&out)
+	})
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "1", Data: *button.CallbackData}})
+	if out != in {
+		t.Errorf("%+v", out)
+	}
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "2", Data: "@unknownkey12"}})
+
+	if _, err := codec.Decode("@unknownkey12", nil); err != (tgbotapi.CallbackExpiredError{Key: "unknownkey12"}) {
+		t.Error(err)
+	}
+
+	if len(answers) != 2 || answers[1] != tgbotapi.DefaultCallbackExpiredText+"|true" {
+		t.Errorf("expected an expired alert, got %q", answers)
+	}
+}
+
+func TestCallbackStoreExpiry(t *testing.T) {
+	store := tgbotapi.NewMemoryCallbackStore()
+
+	store.Set("old", "payload", time.Now().Add(-time.Second))
+	store.Set("new", "payload", time.Now().Add(time.Hour))
+
+	if _, ok, _ := store.Get("old"); ok {
+		t.Error("expired payload was returned")
+	}
+	if payload, ok, _ := store.Get("new"); !ok || payload != "payload" {
+		t.Error(payload, ok)
+	}
+}
+
+func TestDiskCallbackStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tgbotapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := tgbotapi.NewDiskCallbackStore(path.Join(dir, "callbacks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	codec.Store = store
+
+	data, err := codec.Encode("search", largeData{Query: "query", Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec.Store, err = tgbotapi.NewDiskCallbackStore(path.Join(dir, "callbacks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out largeData
+	if prefix, err := codec.Decode(data, &out); err != nil || prefix != "search" || out.Query != "query" {
+		t.Error(prefix, out, err)
+	}
+}
+
+func TestDiskCallbackStoreCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tgbotapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "callbacks.json")
+
+	store, err := tgbotapi.NewDiskCallbackStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 200; i++ {
+		if err := store.Set(strconv.Itoa(i), "expired", time.Now().Add(-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Set("live", "payload", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines >= 200 {
+		t.Errorf("file was not compacted, %d lines", lines)
+	}
+
+	store, err = tgbotapi.NewDiskCallbackStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload, ok, _ := store.Get("live"); !ok || payload != "payload" {
+		t.Error(payload, ok)
+	}
+}
+
+func TestDiskCallbackStoreTruncatedLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tgbotapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "callbacks.json")
+	expires := time.Now().Add(time.Hour).Format(time.RFC3339)
+	data := `{"key":"first","payload":"payload","expires":"` + expires + `"}` + "\n" + `{"key":"cut","pay`
+	if err := ioutil.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := tgbotapi.NewDiskCallbackStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("second", "payload", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = tgbotapi.NewDiskCallbackStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"first", "second"} {
+		if payload, ok, _ := store.Get(key); !ok || payload != "payload" {
+			t.Error(key, payload, ok)
+		}
+	}
+}
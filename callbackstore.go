@@ -0,0 +1,270 @@
+package tgbotapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCallbackTTL is how long a CallbackCodec keeps payloads in its
+// Store if it has no TTL.
+const DefaultCallbackTTL = 24 * time.Hour
+
+// DefaultCallbackExpiredText is shown by a CallbackRouter when a button
+// whose payload expired is pressed.
+const DefaultCallbackExpiredText = "This button has expired."
+
+// callbackStoreMarker starts callback data which is a key of a payload in
+// a CallbackStore.
+const callbackStoreMarker = "@"
+
+// callbackKeySize is the number of random bytes of a CallbackStore key.
+const callbackKeySize = 9
+
+// CallbackStore stores callback payloads which are too large for
+// callback_data under short keys.
+type CallbackStore interface {
+	// Set stores payload under key until expires.
+	Set(key, payload string, expires time.Time) error
+	// Get returns the payload stored under key, if it did not expire.
+	Get(key string) (payload string, ok bool, err error)
+}
+
+// minCallbackPrune is the number of entries from which a
+// MemoryCallbackStore starts removing expired ones.
+const minCallbackPrune = 64
+
+// callbackEntry is a payload stored in a CallbackStore.
+type callbackEntry struct {
+	Payload string    `json:"payload"`
+	Expires time.Time `json:"expires"`
+}
+
+// MemoryCallbackStore is a CallbackStore which keeps payloads in memory.
+type MemoryCallbackStore struct {
+	mu      sync.Mutex
+	entries map[string]callbackEntry
+	pruneAt int
+}
+
+// NewMemoryCallbackStore creates a new, empty MemoryCallbackStore.
+func NewMemoryCallbackStore() *MemoryCallbackStore {
+	return &MemoryCallbackStore{
+		entries: make(map[string]callbackEntry),
+	}
+}
+
+// Set stores payload under key until expires. Expired payloads are
+// removed once the number of payloads doubled since they were removed
+// last.
+func (s *MemoryCallbackStore) Set(key, payload string, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, payload, expires)
+	return nil
+}
+
+// Get returns the payload stored under key, if it did not expire.
+func (s *MemoryCallbackStore) Get(key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.Expires) {
+		return "", false, nil
+	}
+
+	return entry.Payload, true, nil
+}
+
+// set stores an entry, removing expired ones if there are twice as many
+// entries as after they were removed last, so removing them takes
+// constant time per entry on average. s.mu must be held.
+func (s *MemoryCallbackStore) set(key, payload string, expires time.Time) {
+	if s.entries == nil {
+		s.entries = make(map[string]callbackEntry)
+	}
+
+	if len(s.entries) >= minCallbackPrune && len(s.entries) >= s.pruneAt {
+		s.prune()
+	}
+
+	s.entries[key] = callbackEntry{Payload: payload, Expires: expires}
+}
+
+// prune removes expired entries. s.mu must be held.
+func (s *MemoryCallbackStore) prune() {
+	now := time.Now()
+	for k, entry := range s.entries {
+		if now.After(entry.Expires) {
+			delete(s.entries, k)
+		}
+	}
+
+	s.pruneAt = 2 * len(s.entries)
+}
+
+// callbackFileEntry is a line of the file of a DiskCallbackStore.
+type callbackFileEntry struct {
+	Key string `json:"key"`
+	callbackEntry
+}
+
+// DiskCallbackStore is a CallbackStore which keeps payloads in memory and
+// appends them to a file of JSON lines, so buttons keep working after
+// restarts.
+//
+// The file is compacted to the payloads which did not expire once it has
+// twice as many lines.
+type DiskCallbackStore struct {
+	MemoryCallbackStore
+	path string
+	// lines is the number of lines in the file, including payloads which
+	// expired since it was compacted.
+	lines int
+}
+
+// NewDiskCallbackStore creates a DiskCallbackStore stored at path and
+// loads the payloads already stored there, if any.
+func NewDiskCallbackStore(path string) (*DiskCallbackStore, error) {
+	s := &DiskCallbackStore{
+		MemoryCallbackStore: MemoryCallbackStore{entries: make(map[string]callbackEntry)},
+		path:                path,
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	truncated, err := s.load(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if truncated {
+		// Rewrite the file without the partial line, so the next payload
+		// is not appended to it.
+		if err := s.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// load reads the payloads stored in file which did not expire. It reports
+// whether the last line was cut off by a crash while writing it.
+func (s *DiskCallbackStore) load(file io.Reader) (truncated bool, err error) {
+	now := time.Now()
+	dec := json.NewDecoder(file)
+	for {
+		var entry callbackFileEntry
+		err := dec.Decode(&entry)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err == io.ErrUnexpectedEOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		s.lines++
+		if now.Before(entry.Expires) {
+			s.entries[entry.Key] = entry.callbackEntry
+		}
+	}
+}
+
+// Set stores payload under key until expires and appends it to the file.
+func (s *DiskCallbackStore) Set(key, payload string, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, payload, expires)
+
+	if s.lines >= minCallbackPrune && s.lines >= 2*len(s.entries) {
+		return s.compact()
+	}
+
+	line, err := json.Marshal(callbackFileEntry{
+		Key:           key,
+		callbackEntry: callbackEntry{Payload: payload, Expires: expires},
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	s.lines++
+	return nil
+}
+
+// compact rewrites the file with the payloads which did not expire.
+// s.mu must be held.
+func (s *DiskCallbackStore) compact() error {
+	s.prune()
+
+	var data []byte
+	for key, entry := range s.entries {
+		line, err := json.Marshal(callbackFileEntry{Key: key, callbackEntry: entry})
+		if err != nil {
+			return err
+		}
+
+		data = append(append(data, line...), '\n')
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return err
+	}
+
+	s.lines = len(s.entries)
+	return nil
+}
+
+// store saves payload in the Store of the codec and returns the callback
+// data referring to it.
+func (c *CallbackCodec) store(payload string) (string, error) {
+	key := make([]byte, callbackKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultCallbackTTL
+	}
+
+	encodedKey := base64.RawURLEncoding.EncodeToString(key)
+	if err := c.Store.Set(encodedKey, payload, time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
+
+	return callbackStoreMarker + encodedKey, nil
+}
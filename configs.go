@@ -14,6 +14,8 @@ const (
 	APIEndpoint = "https://api.telegram.org/bot%s/%s"
 	// FileEndpoint is the endpoint for downloading a file from Telegram.
 	FileEndpoint = "https://api.telegram.org/file/bot%s/%s"
+	// MaxDownloadFileSize is the largest file in bytes bots can download.
+	MaxDownloadFileSize = 20 << 20
 )
 
 // Constant values for ChatActions
@@ -36,8 +38,29 @@ const (
 
 // Constant values for ParseMode in MessageConfig
 const (
-	ModeMarkdown = "Markdown"
-	ModeHTML     = "HTML"
+	ModeMarkdown   = "Markdown"
+	ModeMarkdownV2 = "MarkdownV2"
+	ModeHTML       = "HTML"
+)
+
+// Constant values for the Type of a MessageEntity
+const (
+	EntityMention       = "mention"
+	EntityHashtag       = "hashtag"
+	EntityCashtag       = "cashtag"
+	EntityBotCommand    = "bot_command"
+	EntityURL           = "url"
+	EntityEmail         = "email"
+	EntityPhoneNumber   = "phone_number"
+	EntityBold          = "bold"
+	EntityItalic        = "italic"
+	EntityUnderline     = "underline"
+	EntityStrikethrough = "strikethrough"
+	EntitySpoiler       = "spoiler"
+	EntityCode          = "code"
+	EntityPre           = "pre"
+	EntityTextLink      = "text_link"
+	EntityTextMention   = "text_mention"
 )
 
 // Library errors
@@ -45,6 +68,74 @@ const (
 	// ErrBadFileType happens when you pass an unknown type
 	ErrBadFileType = "bad file type"
 	ErrBadURL      = "bad or empty url"
+	// ErrNoFilePath happens when Telegram returns a File without a path
+	ErrNoFilePath = "file has no path to download it from"
+	// ErrCallbackDataTooLong happens when encoded callback data is longer
+	// than MaxCallbackDataLength
+	ErrCallbackDataTooLong = "callback data is too long"
+	// ErrCallbackDataType happens when callback data is not a struct of
+	// supported fields
+	ErrCallbackDataType = "unsupported callback data type"
+	// ErrCallbackDataMalformed happens when callback data can not be decoded
+	ErrCallbackDataMalformed = "malformed callback data"
+	// ErrCallbackPrefix happens when a callback prefix is empty or contains
+	// the separator
+	ErrCallbackPrefix = "invalid callback prefix"
+	// ErrCallbackSignature happens when the signature of callback data is
+	// invalid
+	ErrCallbackSignature = "invalid callback data signature"
+	// ErrCallbackNoHandler happens when no handler exists for callback data
+	ErrCallbackNoHandler = "no handler for callback data"
+	// ErrCallbackExpired happens when stored callback data expired
+	ErrCallbackExpired = "callback data expired"
+	// ErrMenuNodeID happens when a menu contains two nodes with the same ID
+	ErrMenuNodeID = "duplicate menu node ID"
+	// ErrMenuNodeNil happens when a menu is created without a root node
+	ErrMenuNodeNil = "menu node is nil"
+	// ErrMenuNode happens when callback data refers to an unknown menu node
+	ErrMenuNode = "menu node not found"
+	// ErrMenuItem happens when callback data refers to an unknown menu item
+	ErrMenuItem = "menu item not found"
+	// ErrKeyboardButtonText happens when a keyboard button has no text
+	ErrKeyboardButtonText = "keyboard button text is empty"
+	// ErrKeyboardButtonFields happens when a keyboard button has more than
+	// one optional field set
+	ErrKeyboardButtonFields = "keyboard button must have at most one optional field"
+	// ErrInlineKeyboardButtonFields happens when an inline keyboard button
+	// does not have exactly one optional field set
+	ErrInlineKeyboardButtonFields = "inline keyboard button must have exactly one optional field"
+	// ErrInlineKeyboardButtonPosition happens when a callback game or pay
+	// button is not the first button of the first row
+	ErrInlineKeyboardButtonPosition = "callback game and pay buttons must be the first button in the first row"
+	// ErrKeyboardRowTooLong happens when a keyboard row has more buttons
+	// than Telegram allows
+	ErrKeyboardRowTooLong = "keyboard row has too many buttons"
+	// ErrKeyboardTooManyButtons happens when a keyboard has more buttons
+	// than Telegram allows
+	ErrKeyboardTooManyButtons = "keyboard has too many buttons"
+	// ErrKeyboardButtonKind happens when a keyboard builder contains
+	// buttons of the other keyboard type
+	ErrKeyboardButtonKind = "keyboard contains buttons of the wrong kind"
+	// ErrInlineResultID happens when an inline query result ID is empty or
+	// longer than MaxInlineResultIDLength bytes
+	ErrInlineResultID = "inline query result ID must be 1 to 64 bytes long"
+	// ErrInlineResultIDDuplicate happens when inline query results share
+	// an ID
+	ErrInlineResultIDDuplicate = "duplicate inline query result ID"
+	// ErrInlineResultNil happens when inline query results contain nil
+	ErrInlineResultNil = "inline query result is nil"
+	// ErrLivePeriod happens when streaming a live location without a live
+	// period
+	ErrLivePeriod = "live period is required for a live location"
+	// ErrLivePeriodRange happens when the live period of a location is
+	// outside of MinLivePeriod and MaxLivePeriod
+	ErrLivePeriodRange = "live period must be between 60 and 86400 seconds"
+)
+
+// Telegram's limits on the live period of a location in seconds.
+const (
+	MinLivePeriod = 60
+	MaxLivePeriod = 86400
 )
 
 // Chattable is any config type that can be sent.
@@ -57,9 +148,9 @@ type Chattable interface {
 type Fileable interface {
 	Chattable
 	params() (map[string]string, error)
-	name() string
-	getFile() interface{}
+	files() []RequestFile
 	useExistingFile() bool
+	uploadProgress() UploadProgressFunc
 }
 
 // BaseChat is base type for all chat config types.
@@ -85,12 +176,12 @@ func (chat *BaseChat) values() (url.Values, error) {
 	}
 
 	if chat.ReplyMarkup != nil {
-		data, err := json.Marshal(chat.ReplyMarkup)
+		data, err := marshalReplyMarkup(chat.ReplyMarkup)
 		if err != nil {
 			return v, err
 		}
 
-		v.Add("reply_markup", string(data))
+		v.Add("reply_markup", data)
 	}
 
 	v.Add("disable_notification", strconv.FormatBool(chat.DisableNotification))
@@ -106,6 +197,9 @@ type BaseFile struct {
 	UseExisting bool
 	MimeType    string
 	FileSize    int
+
+	// UploadProgress is called while the File is uploaded.
+	UploadProgress UploadProgressFunc
 }
 
 // params returns a map[string]string representation of BaseFile.
@@ -123,12 +217,12 @@ func (file BaseFile) params() (map[string]string, error) {
 	}
 
 	if file.ReplyMarkup != nil {
-		data, err := json.Marshal(file.ReplyMarkup)
+		data, err := marshalReplyMarkup(file.ReplyMarkup)
 		if err != nil {
 			return params, err
 		}
 
-		params["reply_markup"] = string(data)
+		params["reply_markup"] = data
 	}
 
 	if file.MimeType != "" {
@@ -144,9 +238,20 @@ func (file BaseFile) params() (map[string]string, error) {
 	return params, nil
 }
 
-// getFile returns the file.
-func (file BaseFile) getFile() interface{} {
-	return file.File
+// requestFiles returns the File to upload as the field name, unless an
+// existing file is used, followed by thumb if it is set.
+func (file BaseFile) requestFiles(name string, thumb interface{}) []RequestFile {
+	var files []RequestFile
+
+	if !file.UseExisting {
+		files = append(files, RequestFile{Name: name, File: file.File})
+	}
+
+	if thumb != nil {
+		files = append(files, RequestFile{Name: "thumb", File: thumb})
+	}
+
+	return files
 }
 
 // useExistingFile returns if the BaseFile has already been uploaded.
@@ -154,6 +259,11 @@ func (file BaseFile) useExistingFile() bool {
 	return file.UseExisting
 }
 
+// uploadProgress returns the function to report upload progress to.
+func (file BaseFile) uploadProgress() UploadProgressFunc {
+	return file.UploadProgress
+}
+
 // BaseEdit is base type of all chat edits.
 type BaseEdit struct {
 	ChatID          int64
@@ -178,21 +288,54 @@ func (edit BaseEdit) values() (url.Values, error) {
 	}
 
 	if edit.ReplyMarkup != nil {
-		data, err := json.Marshal(edit.ReplyMarkup)
+		data, err := marshalReplyMarkup(edit.ReplyMarkup)
 		if err != nil {
 			return v, err
 		}
-		v.Add("reply_markup", string(data))
+		v.Add("reply_markup", data)
 	}
 
 	return v, nil
 }
 
+// addEntities adds JSON encoded entities to url.Values if there are any.
+func addEntities(v url.Values, key string, entities []MessageEntity) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(entities)
+	if err != nil {
+		return err
+	}
+
+	v.Add(key, string(data))
+
+	return nil
+}
+
+// addEntitiesParam adds JSON encoded entities to params if there are any.
+func addEntitiesParam(params map[string]string, key string, entities []MessageEntity) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(entities)
+	if err != nil {
+		return err
+	}
+
+	params[key] = string(data)
+
+	return nil
+}
+
 // MessageConfig contains information about a SendMessage request.
 type MessageConfig struct {
 	BaseChat
 	Text                  string
 	ParseMode             string
+	Entities              []MessageEntity
 	DisableWebPagePreview bool
 }
 
@@ -207,6 +350,9 @@ func (config MessageConfig) values() (url.Values, error) {
 	if config.ParseMode != "" {
 		v.Add("parse_mode", config.ParseMode)
 	}
+	if err := addEntities(v, "entities", config.Entities); err != nil {
+		return v, err
+	}
 
 	return v, nil
 }
@@ -243,19 +389,26 @@ func (config ForwardConfig) method() string {
 // PhotoConfig contains information about a SendPhoto request.
 type PhotoConfig struct {
 	BaseFile
-	Caption   string
-	ParseMode string
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
 }
 
 // Params returns a map[string]string representation of PhotoConfig.
 func (config PhotoConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Caption != "" {
 		params["caption"] = config.Caption
 		if config.ParseMode != "" {
 			params["parse_mode"] = config.ParseMode
 		}
+		if err := addEntitiesParam(params, "caption_entities", config.CaptionEntities); err != nil {
+			return params, err
+		}
 	}
 
 	return params, nil
@@ -274,6 +427,9 @@ func (config PhotoConfig) values() (url.Values, error) {
 		if config.ParseMode != "" {
 			v.Add("parse_mode", config.ParseMode)
 		}
+		if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+			return v, err
+		}
 	}
 
 	return v, nil
@@ -284,6 +440,11 @@ func (config PhotoConfig) name() string {
 	return "photo"
 }
 
+// files returns the files to upload for the Photo.
+func (config PhotoConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), nil)
+}
+
 // method returns Telegram API method name for sending Photo.
 func (config PhotoConfig) method() string {
 	return "sendPhoto"
@@ -292,11 +453,13 @@ func (config PhotoConfig) method() string {
 // AudioConfig contains information about a SendAudio request.
 type AudioConfig struct {
 	BaseFile
-	Caption   string
-	ParseMode string
-	Duration  int
-	Performer string
-	Title     string
+	Thumb           interface{} // path, FileBytes or FileReader
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
+	Duration        int
+	Performer       string
+	Title           string
 }
 
 // values returns a url.Values representation of AudioConfig.
@@ -322,6 +485,9 @@ func (config AudioConfig) values() (url.Values, error) {
 		if config.ParseMode != "" {
 			v.Add("parse_mode", config.ParseMode)
 		}
+		if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+			return v, err
+		}
 	}
 
 	return v, nil
@@ -329,7 +495,10 @@ func (config AudioConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of AudioConfig.
 func (config AudioConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Duration != 0 {
 		params["duration"] = strconv.Itoa(config.Duration)
@@ -346,6 +515,9 @@ func (config AudioConfig) params() (map[string]string, error) {
 		if config.ParseMode != "" {
 			params["parse_mode"] = config.ParseMode
 		}
+		if err := addEntitiesParam(params, "caption_entities", config.CaptionEntities); err != nil {
+			return params, err
+		}
 	}
 
 	return params, nil
@@ -356,6 +528,11 @@ func (config AudioConfig) name() string {
 	return "audio"
 }
 
+// files returns the files to upload for the Audio.
+func (config AudioConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), config.Thumb)
+}
+
 // method returns Telegram API method name for sending Audio.
 func (config AudioConfig) method() string {
 	return "sendAudio"
@@ -364,8 +541,10 @@ func (config AudioConfig) method() string {
 // DocumentConfig contains information about a SendDocument request.
 type DocumentConfig struct {
 	BaseFile
-	Caption   string
-	ParseMode string
+	Thumb           interface{} // path, FileBytes or FileReader
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
 }
 
 // values returns a url.Values representation of DocumentConfig.
@@ -381,6 +560,9 @@ func (config DocumentConfig) values() (url.Values, error) {
 		if config.ParseMode != "" {
 			v.Add("parse_mode", config.ParseMode)
 		}
+		if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+			return v, err
+		}
 	}
 
 	return v, nil
@@ -388,13 +570,19 @@ func (config DocumentConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of DocumentConfig.
 func (config DocumentConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Caption != "" {
 		params["caption"] = config.Caption
 		if config.ParseMode != "" {
 			params["parse_mode"] = config.ParseMode
 		}
+		if err := addEntitiesParam(params, "caption_entities", config.CaptionEntities); err != nil {
+			return params, err
+		}
 	}
 
 	return params, nil
@@ -405,6 +593,11 @@ func (config DocumentConfig) name() string {
 	return "document"
 }
 
+// files returns the files to upload for the Document.
+func (config DocumentConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), config.Thumb)
+}
+
 // method returns Telegram API method name for sending Document.
 func (config DocumentConfig) method() string {
 	return "sendDocument"
@@ -429,7 +622,10 @@ func (config StickerConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of StickerConfig.
 func (config StickerConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	return params, nil
 }
@@ -439,6 +635,11 @@ func (config StickerConfig) name() string {
 	return "sticker"
 }
 
+// files returns the files to upload for the Sticker.
+func (config StickerConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), nil)
+}
+
 // method returns Telegram API method name for sending Sticker.
 func (config StickerConfig) method() string {
 	return "sendSticker"
@@ -447,9 +648,11 @@ func (config StickerConfig) method() string {
 // VideoConfig contains information about a SendVideo request.
 type VideoConfig struct {
 	BaseFile
-	Duration  int
-	Caption   string
-	ParseMode string
+	Thumb           interface{} // path, FileBytes or FileReader
+	Duration        int
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
 }
 
 // values returns a url.Values representation of VideoConfig.
@@ -468,6 +671,9 @@ func (config VideoConfig) values() (url.Values, error) {
 		if config.ParseMode != "" {
 			v.Add("parse_mode", config.ParseMode)
 		}
+		if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+			return v, err
+		}
 	}
 
 	return v, nil
@@ -475,13 +681,19 @@ func (config VideoConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of VideoConfig.
 func (config VideoConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Caption != "" {
 		params["caption"] = config.Caption
 		if config.ParseMode != "" {
 			params["parse_mode"] = config.ParseMode
 		}
+		if err := addEntitiesParam(params, "caption_entities", config.CaptionEntities); err != nil {
+			return params, err
+		}
 	}
 
 	return params, nil
@@ -492,6 +704,11 @@ func (config VideoConfig) name() string {
 	return "video"
 }
 
+// files returns the files to upload for the Video.
+func (config VideoConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), config.Thumb)
+}
+
 // method returns Telegram API method name for sending Video.
 func (config VideoConfig) method() string {
 	return "sendVideo"
@@ -500,9 +717,11 @@ func (config VideoConfig) method() string {
 // AnimationConfig contains information about a SendAnimation request.
 type AnimationConfig struct {
 	BaseFile
-	Duration  int
-	Caption   string
-	ParseMode string
+	Thumb           interface{} // path, FileBytes or FileReader
+	Duration        int
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
 }
 
 // values returns a url.Values representation of AnimationConfig.
@@ -521,6 +740,9 @@ func (config AnimationConfig) values() (url.Values, error) {
 		if config.ParseMode != "" {
 			v.Add("parse_mode", config.ParseMode)
 		}
+		if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+			return v, err
+		}
 	}
 
 	return v, nil
@@ -528,13 +750,19 @@ func (config AnimationConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of AnimationConfig.
 func (config AnimationConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Caption != "" {
 		params["caption"] = config.Caption
 		if config.ParseMode != "" {
 			params["parse_mode"] = config.ParseMode
 		}
+		if err := addEntitiesParam(params, "caption_entities", config.CaptionEntities); err != nil {
+			return params, err
+		}
 	}
 
 	return params, nil
@@ -545,6 +773,11 @@ func (config AnimationConfig) name() string {
 	return "animation"
 }
 
+// files returns the files to upload for the Animation.
+func (config AnimationConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), config.Thumb)
+}
+
 // method returns Telegram API method name for sending Animation.
 func (config AnimationConfig) method() string {
 	return "sendAnimation"
@@ -553,6 +786,7 @@ func (config AnimationConfig) method() string {
 // VideoNoteConfig contains information about a SendVideoNote request.
 type VideoNoteConfig struct {
 	BaseFile
+	Thumb    interface{} // path, FileBytes or FileReader
 	Duration int
 	Length   int
 }
@@ -579,7 +813,10 @@ func (config VideoNoteConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of VideoNoteConfig.
 func (config VideoNoteConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Length != 0 {
 		params["length"] = strconv.Itoa(config.Length)
@@ -596,6 +833,11 @@ func (config VideoNoteConfig) name() string {
 	return "video_note"
 }
 
+// files returns the files to upload for the VideoNote.
+func (config VideoNoteConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), config.Thumb)
+}
+
 // method returns Telegram API method name for sending VideoNote.
 func (config VideoNoteConfig) method() string {
 	return "sendVideoNote"
@@ -604,9 +846,10 @@ func (config VideoNoteConfig) method() string {
 // VoiceConfig contains information about a SendVoice request.
 type VoiceConfig struct {
 	BaseFile
-	Caption   string
-	ParseMode string
-	Duration  int
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
+	Duration        int
 }
 
 // values returns a url.Values representation of VoiceConfig.
@@ -625,6 +868,9 @@ func (config VoiceConfig) values() (url.Values, error) {
 		if config.ParseMode != "" {
 			v.Add("parse_mode", config.ParseMode)
 		}
+		if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+			return v, err
+		}
 	}
 
 	return v, nil
@@ -632,7 +878,10 @@ func (config VoiceConfig) values() (url.Values, error) {
 
 // params returns a map[string]string representation of VoiceConfig.
 func (config VoiceConfig) params() (map[string]string, error) {
-	params, _ := config.BaseFile.params()
+	params, err := config.BaseFile.params()
+	if err != nil {
+		return params, err
+	}
 
 	if config.Duration != 0 {
 		params["duration"] = strconv.Itoa(config.Duration)
@@ -642,6 +891,9 @@ func (config VoiceConfig) params() (map[string]string, error) {
 		if config.ParseMode != "" {
 			params["parse_mode"] = config.ParseMode
 		}
+		if err := addEntitiesParam(params, "caption_entities", config.CaptionEntities); err != nil {
+			return params, err
+		}
 	}
 
 	return params, nil
@@ -652,6 +904,11 @@ func (config VoiceConfig) name() string {
 	return "voice"
 }
 
+// files returns the files to upload for the Voice.
+func (config VoiceConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), nil)
+}
+
 // method returns Telegram API method name for sending Voice.
 func (config VoiceConfig) method() string {
 	return "sendVoice"
@@ -669,7 +926,9 @@ func (config MediaGroupConfig) values() (url.Values, error) {
 		return v, err
 	}
 
-	data, err := json.Marshal(config.InputMedia)
+	media, _ := prepareInputMedia(config.InputMedia)
+
+	data, err := json.Marshal(media)
 	if err != nil {
 		return v, err
 	}
@@ -679,15 +938,113 @@ func (config MediaGroupConfig) values() (url.Values, error) {
 	return v, nil
 }
 
+// params returns a map[string]string representation of MediaGroupConfig.
+func (config MediaGroupConfig) params() (map[string]string, error) {
+	v, err := config.values()
+	if err != nil {
+		return nil, err
+	}
+
+	return valuesToParams(v), nil
+}
+
+// files returns the media of the group which must be uploaded.
+func (config MediaGroupConfig) files() []RequestFile {
+	_, files := prepareInputMedia(config.InputMedia)
+
+	return files
+}
+
 func (config MediaGroupConfig) method() string {
 	return "sendMediaGroup"
 }
 
+// valuesToParams flattens v to a map[string]string for multipart uploads.
+func valuesToParams(v url.Values) map[string]string {
+	params := make(map[string]string, len(v))
+	for key := range v {
+		params[key] = v.Get(key)
+	}
+
+	return params
+}
+
+// prepareInputMedia replaces all media and thumbnails which must be uploaded
+// with an attach:// reference and returns the files to upload under those
+// names.
+// Pointers to media are replaced by copies of the media they point to.
+func prepareInputMedia(inputMedia []interface{}) ([]interface{}, []RequestFile) {
+	var files []RequestFile
+	prepared := make([]interface{}, len(inputMedia))
+
+	for i, media := range inputMedia {
+		name := "file-" + strconv.Itoa(i)
+		attach := func(name string, file interface{}) interface{} {
+			switch file.(type) {
+			case FilePath, FileBytes, FileReader:
+				files = append(files, RequestFile{Name: name, File: file})
+				return "attach://" + name
+			}
+
+			return file
+		}
+
+		switch m := media.(type) {
+		case *InputMediaPhoto:
+			if m != nil {
+				media = *m
+			}
+		case *InputMediaVideo:
+			if m != nil {
+				media = *m
+			}
+		case *InputMediaAnimation:
+			if m != nil {
+				media = *m
+			}
+		case *InputMediaAudio:
+			if m != nil {
+				media = *m
+			}
+		case *InputMediaDocument:
+			if m != nil {
+				media = *m
+			}
+		}
+
+		switch m := media.(type) {
+		case InputMediaPhoto:
+			m.Media = attach(name, m.Media)
+			prepared[i] = m
+		case InputMediaVideo:
+			m.Media = attach(name, m.Media)
+			m.Thumb = attach(name+"-thumb", m.Thumb)
+			prepared[i] = m
+		case InputMediaAnimation:
+			m.Media = attach(name, m.Media)
+			prepared[i] = m
+		case InputMediaAudio:
+			m.Media = attach(name, m.Media)
+			prepared[i] = m
+		case InputMediaDocument:
+			m.Media = attach(name, m.Media)
+			prepared[i] = m
+		default:
+			prepared[i] = media
+		}
+	}
+
+	return prepared, files
+}
+
 // LocationConfig contains information about a SendLocation request.
 type LocationConfig struct {
 	BaseChat
 	Latitude  float64 // required
 	Longitude float64 // required
+	// LivePeriod is the time in seconds the location can be updated with
+	// an EditMessageLiveLocationConfig, between 60 and 86400.
+	LivePeriod int // optional
 }
 
 // values returns a url.Values representation of LocationConfig.
@@ -697,8 +1054,15 @@ func (config LocationConfig) values() (url.Values, error) {
 		return v, err
 	}
 
+	if err := validateLivePeriod(config.LivePeriod); err != nil {
+		return v, err
+	}
+
 	v.Add("latitude", strconv.FormatFloat(config.Latitude, 'f', 6, 64))
 	v.Add("longitude", strconv.FormatFloat(config.Longitude, 'f', 6, 64))
+	if config.LivePeriod != 0 {
+		v.Add("live_period", strconv.Itoa(config.LivePeriod))
+	}
 
 	return v, nil
 }
@@ -708,6 +1072,66 @@ func (config LocationConfig) method() string {
 	return "sendLocation"
 }
 
+// EditMessageLiveLocationConfig allows you to update the location of a
+// live location message.
+type EditMessageLiveLocationConfig struct {
+	BaseEdit
+	Latitude             float64 // required
+	Longitude            float64 // required
+	HorizontalAccuracy   float64 // optional
+	Heading              int     // optional
+	ProximityAlertRadius int     // optional
+	// LivePeriod replaces the live period of the location, between 60 and
+	// 86400 seconds from when it was sent.
+	LivePeriod int // optional
+}
+
+func (config EditMessageLiveLocationConfig) values() (url.Values, error) {
+	v, err := config.BaseEdit.values()
+	if err != nil {
+		return v, err
+	}
+
+	if err := validateLivePeriod(config.LivePeriod); err != nil {
+		return v, err
+	}
+
+	v.Add("latitude", strconv.FormatFloat(config.Latitude, 'f', 6, 64))
+	v.Add("longitude", strconv.FormatFloat(config.Longitude, 'f', 6, 64))
+	if config.LivePeriod != 0 {
+		v.Add("live_period", strconv.Itoa(config.LivePeriod))
+	}
+	if config.HorizontalAccuracy != 0 {
+		v.Add("horizontal_accuracy", strconv.FormatFloat(config.HorizontalAccuracy, 'f', -1, 64))
+	}
+	if config.Heading != 0 {
+		v.Add("heading", strconv.Itoa(config.Heading))
+	}
+	if config.ProximityAlertRadius != 0 {
+		v.Add("proximity_alert_radius", strconv.Itoa(config.ProximityAlertRadius))
+	}
+
+	return v, nil
+}
+
+func (config EditMessageLiveLocationConfig) method() string {
+	return "editMessageLiveLocation"
+}
+
+// StopMessageLiveLocationConfig allows you to stop updating a live
+// location message before its live period expires.
+type StopMessageLiveLocationConfig struct {
+	BaseEdit
+}
+
+func (config StopMessageLiveLocationConfig) values() (url.Values, error) {
+	return config.BaseEdit.values()
+}
+
+func (config StopMessageLiveLocationConfig) method() string {
+	return "stopMessageLiveLocation"
+}
+
 // VenueConfig contains information about a SendVenue request.
 type VenueConfig struct {
 	BaseChat
@@ -787,7 +1211,7 @@ func (config GameConfig) method() string {
 
 // SetGameScoreConfig allows you to update the game score in a chat.
 type SetGameScoreConfig struct {
-	UserID             int
+	UserID             int64
 	Score              int
 	Force              bool
 	DisableEditMessage bool
@@ -800,7 +1224,7 @@ type SetGameScoreConfig struct {
 func (config SetGameScoreConfig) values() (url.Values, error) {
 	v := url.Values{}
 
-	v.Add("user_id", strconv.Itoa(config.UserID))
+	v.Add("user_id", strconv.FormatInt(config.UserID, 10))
 	v.Add("score", strconv.Itoa(config.Score))
 	if config.InlineMessageID == "" {
 		if config.ChannelUsername == "" {
@@ -823,7 +1247,7 @@ func (config SetGameScoreConfig) method() string {
 
 // GetGameHighScoresConfig allows you to fetch the high scores for a game.
 type GetGameHighScoresConfig struct {
-	UserID          int
+	UserID          int64
 	ChatID          int
 	ChannelUsername string
 	MessageID       int
@@ -833,7 +1257,7 @@ type GetGameHighScoresConfig struct {
 func (config GetGameHighScoresConfig) values() (url.Values, error) {
 	v := url.Values{}
 
-	v.Add("user_id", strconv.Itoa(config.UserID))
+	v.Add("user_id", strconv.FormatInt(config.UserID, 10))
 	if config.InlineMessageID == "" {
 		if config.ChannelUsername == "" {
 			v.Add("chat_id", strconv.Itoa(config.ChatID))
@@ -878,6 +1302,7 @@ type EditMessageTextConfig struct {
 	BaseEdit
 	Text                  string
 	ParseMode             string
+	Entities              []MessageEntity
 	DisableWebPagePreview bool
 }
 
@@ -890,6 +1315,9 @@ func (config EditMessageTextConfig) values() (url.Values, error) {
 	v.Add("text", config.Text)
 	v.Add("parse_mode", config.ParseMode)
 	v.Add("disable_web_page_preview", strconv.FormatBool(config.DisableWebPagePreview))
+	if err := addEntities(v, "entities", config.Entities); err != nil {
+		return v, err
+	}
 
 	return v, nil
 }
@@ -901,8 +1329,9 @@ func (config EditMessageTextConfig) method() string {
 // EditMessageCaptionConfig allows you to modify the caption of a message.
 type EditMessageCaptionConfig struct {
 	BaseEdit
-	Caption   string
-	ParseMode string
+	Caption         string
+	ParseMode       string
+	CaptionEntities []MessageEntity
 }
 
 func (config EditMessageCaptionConfig) values() (url.Values, error) {
@@ -912,6 +1341,9 @@ func (config EditMessageCaptionConfig) values() (url.Values, error) {
 	if config.ParseMode != "" {
 		v.Add("parse_mode", config.ParseMode)
 	}
+	if err := addEntities(v, "caption_entities", config.CaptionEntities); err != nil {
+		return v, err
+	}
 
 	return v, nil
 }
@@ -934,19 +1366,99 @@ func (config EditMessageReplyMarkupConfig) method() string {
 	return "editMessageReplyMarkup"
 }
 
+// EditMessageMediaConfig allows you to replace the media of a message.
+//
+// Media is an InputMediaPhoto, InputMediaVideo, InputMediaAnimation,
+// InputMediaAudio or InputMediaDocument. Its media is uploaded if it is a
+// FilePath, FileBytes or FileReader.
+type EditMessageMediaConfig struct {
+	BaseEdit
+	Media interface{}
+}
+
+func (config EditMessageMediaConfig) values() (url.Values, error) {
+	v, err := config.BaseEdit.values()
+	if err != nil {
+		return v, err
+	}
+
+	media, _ := prepareInputMedia([]interface{}{config.Media})
+
+	data, err := json.Marshal(media[0])
+	if err != nil {
+		return v, err
+	}
+
+	v.Add("media", string(data))
+
+	return v, nil
+}
+
+// params returns a map[string]string representation of
+// EditMessageMediaConfig.
+func (config EditMessageMediaConfig) params() (map[string]string, error) {
+	v, err := config.values()
+	if err != nil {
+		return nil, err
+	}
+
+	return valuesToParams(v), nil
+}
+
+// files returns the media which must be uploaded.
+func (config EditMessageMediaConfig) files() []RequestFile {
+	_, files := prepareInputMedia([]interface{}{config.Media})
+
+	return files
+}
+
+func (config EditMessageMediaConfig) method() string {
+	return "editMessageMedia"
+}
+
 // UserProfilePhotosConfig contains information about a
 // GetUserProfilePhotos request.
 type UserProfilePhotosConfig struct {
-	UserID int
+	UserID int64
 	Offset int
 	Limit  int
 }
 
+func (config UserProfilePhotosConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	v.Add("user_id", strconv.FormatInt(config.UserID, 10))
+	if config.Offset != 0 {
+		v.Add("offset", strconv.Itoa(config.Offset))
+	}
+	if config.Limit != 0 {
+		v.Add("limit", strconv.Itoa(config.Limit))
+	}
+
+	return v, nil
+}
+
+func (config UserProfilePhotosConfig) method() string {
+	return "getUserProfilePhotos"
+}
+
 // FileConfig has information about a file hosted on Telegram.
 type FileConfig struct {
 	FileID string
 }
 
+func (config FileConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	v.Add("file_id", config.FileID)
+
+	return v, nil
+}
+
+func (config FileConfig) method() string {
+	return "getFile"
+}
+
 // UpdateConfig contains information about a GetUpdates request.
 type UpdateConfig struct {
 	Offset  int
@@ -955,12 +1467,83 @@ type UpdateConfig struct {
 }
 
 // WebhookConfig contains information about a SetWebhook request.
+//
+// A WebhookConfig without URL removes the webhook.
 type WebhookConfig struct {
 	URL            *url.URL
 	Certificate    interface{}
 	MaxConnections int
 }
 
+func (config WebhookConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	if config.URL != nil {
+		v.Add("url", config.URL.String())
+	}
+	if config.MaxConnections != 0 {
+		v.Add("max_connections", strconv.Itoa(config.MaxConnections))
+	}
+
+	return v, nil
+}
+
+// params returns a map[string]string representation of WebhookConfig.
+func (config WebhookConfig) params() (map[string]string, error) {
+	v, err := config.values()
+	if err != nil {
+		return nil, err
+	}
+
+	return valuesToParams(v), nil
+}
+
+// files returns the Certificate to upload, if there is one.
+func (config WebhookConfig) files() []RequestFile {
+	if config.Certificate == nil {
+		return nil
+	}
+
+	return []RequestFile{{Name: "certificate", File: config.Certificate}}
+}
+
+func (config WebhookConfig) useExistingFile() bool {
+	return false
+}
+
+func (config WebhookConfig) uploadProgress() UploadProgressFunc {
+	return nil
+}
+
+func (config WebhookConfig) method() string {
+	return "setWebhook"
+}
+
+// GetWebhookInfoConfig contains information about a GetWebhookInfo
+// request.
+type GetWebhookInfoConfig struct{}
+
+func (config GetWebhookInfoConfig) values() (url.Values, error) {
+	return url.Values{}, nil
+}
+
+func (config GetWebhookInfoConfig) method() string {
+	return "getWebhookInfo"
+}
+
+// RequestFile is a file to upload as the field Name of a request.
+//
+// File should be a string or FilePath to a file path, a FileBytes struct,
+// a FileReader struct, or a url.URL.
+type RequestFile struct {
+	Name string
+	File interface{}
+}
+
+// FilePath is a path to a local file to upload. It is used where a plain
+// string would instead be a file ID or URL.
+type FilePath string
+
 // FileBytes contains information about a set of bytes to upload
 // as a File.
 type FileBytes struct {
@@ -969,23 +1552,51 @@ type FileBytes struct {
 }
 
 // FileReader contains information about a reader to upload as a File.
-// If Size is -1, it will read the entire Reader into memory to
-// calculate a Size.
+// The Reader is streamed as it is uploaded, so Size may be -1 if it
+// is unknown. Otherwise it must be the exact size of the Reader, since
+// it determines the length of the request.
 type FileReader struct {
 	Name   string
 	Reader io.Reader
 	Size   int64
 }
 
+// UploadProgressFunc is called while uploading files with the number of
+// bytes sent so far and the total number of bytes, which is -1 if the
+// size of a FileReader is unknown.
+type UploadProgressFunc func(sent, total int64)
+
 // InlineConfig contains information on making an InlineQuery response.
 type InlineConfig struct {
-	InlineQueryID     string        `json:"inline_query_id"`
-	Results           []interface{} `json:"results"`
-	CacheTime         int           `json:"cache_time"`
-	IsPersonal        bool          `json:"is_personal"`
-	NextOffset        string        `json:"next_offset"`
-	SwitchPMText      string        `json:"switch_pm_text"`
-	SwitchPMParameter string        `json:"switch_pm_parameter"`
+	InlineQueryID     string              `json:"inline_query_id"`
+	Results           []InlineQueryResult `json:"results"`
+	CacheTime         int                 `json:"cache_time"`
+	IsPersonal        bool                `json:"is_personal"`
+	NextOffset        string              `json:"next_offset"`
+	SwitchPMText      string              `json:"switch_pm_text"`
+	SwitchPMParameter string              `json:"switch_pm_parameter"`
+}
+
+func (config InlineConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	v.Add("inline_query_id", config.InlineQueryID)
+	v.Add("cache_time", strconv.Itoa(config.CacheTime))
+	v.Add("is_personal", strconv.FormatBool(config.IsPersonal))
+	v.Add("next_offset", config.NextOffset)
+	data, err := marshalInlineQueryResults(config.Results)
+	if err != nil {
+		return v, err
+	}
+	v.Add("results", data)
+	v.Add("switch_pm_text", config.SwitchPMText)
+	v.Add("switch_pm_parameter", config.SwitchPMParameter)
+
+	return v, nil
+}
+
+func (config InlineConfig) method() string {
+	return "answerInlineQuery"
 }
 
 // CallbackConfig contains information on making a CallbackQuery response.
@@ -997,13 +1608,63 @@ type CallbackConfig struct {
 	CacheTime       int    `json:"cache_time"`
 }
 
+func (config CallbackConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	v.Add("callback_query_id", config.CallbackQueryID)
+	if config.Text != "" {
+		v.Add("text", config.Text)
+	}
+	v.Add("show_alert", strconv.FormatBool(config.ShowAlert))
+	if config.URL != "" {
+		v.Add("url", config.URL)
+	}
+	v.Add("cache_time", strconv.Itoa(config.CacheTime))
+
+	return v, nil
+}
+
+func (config CallbackConfig) method() string {
+	return "answerCallbackQuery"
+}
+
 // ChatMemberConfig contains information about a user in a chat for use
 // with administrative functions such as kicking or unbanning a user.
 type ChatMemberConfig struct {
 	ChatID             int64
 	SuperGroupUsername string
 	ChannelUsername    string
-	UserID             int
+	UserID             int64
+}
+
+// chatMemberValues returns the chat and user of ChatMemberConfig as
+// url.Values.
+func (config ChatMemberConfig) chatMemberValues() url.Values {
+	v := url.Values{}
+
+	if config.SuperGroupUsername != "" {
+		v.Add("chat_id", config.SuperGroupUsername)
+	} else if config.ChannelUsername != "" {
+		v.Add("chat_id", config.ChannelUsername)
+	} else {
+		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
+	}
+	v.Add("user_id", strconv.FormatInt(config.UserID, 10))
+
+	return v
+}
+
+// UnbanChatMemberConfig allows you to unban a user.
+type UnbanChatMemberConfig struct {
+	ChatMemberConfig
+}
+
+func (config UnbanChatMemberConfig) values() (url.Values, error) {
+	return config.chatMemberValues(), nil
+}
+
+func (config UnbanChatMemberConfig) method() string {
+	return "unbanChatMember"
 }
 
 // KickChatMemberConfig contains extra fields to kick user
@@ -1012,6 +1673,20 @@ type KickChatMemberConfig struct {
 	UntilDate int64
 }
 
+func (config KickChatMemberConfig) values() (url.Values, error) {
+	v := config.chatMemberValues()
+
+	if config.UntilDate != 0 {
+		v.Add("until_date", strconv.FormatInt(config.UntilDate, 10))
+	}
+
+	return v, nil
+}
+
+func (config KickChatMemberConfig) method() string {
+	return "kickChatMember"
+}
+
 // RestrictChatMemberConfig contains fields to restrict members of chat
 type RestrictChatMemberConfig struct {
 	ChatMemberConfig
@@ -1022,6 +1697,32 @@ type RestrictChatMemberConfig struct {
 	CanAddWebPagePreviews *bool
 }
 
+func (config RestrictChatMemberConfig) values() (url.Values, error) {
+	v := config.chatMemberValues()
+
+	if config.CanSendMessages != nil {
+		v.Add("can_send_messages", strconv.FormatBool(*config.CanSendMessages))
+	}
+	if config.CanSendMediaMessages != nil {
+		v.Add("can_send_media_messages", strconv.FormatBool(*config.CanSendMediaMessages))
+	}
+	if config.CanSendOtherMessages != nil {
+		v.Add("can_send_other_messages", strconv.FormatBool(*config.CanSendOtherMessages))
+	}
+	if config.CanAddWebPagePreviews != nil {
+		v.Add("can_add_web_page_previews", strconv.FormatBool(*config.CanAddWebPagePreviews))
+	}
+	if config.UntilDate != 0 {
+		v.Add("until_date", strconv.FormatInt(config.UntilDate, 10))
+	}
+
+	return v, nil
+}
+
+func (config RestrictChatMemberConfig) method() string {
+	return "restrictChatMember"
+}
+
 // PromoteChatMemberConfig contains fields to promote members of chat
 type PromoteChatMemberConfig struct {
 	ChatMemberConfig
@@ -1035,18 +1736,91 @@ type PromoteChatMemberConfig struct {
 	CanPromoteMembers  *bool
 }
 
+func (config PromoteChatMemberConfig) values() (url.Values, error) {
+	v := config.chatMemberValues()
+
+	if config.CanChangeInfo != nil {
+		v.Add("can_change_info", strconv.FormatBool(*config.CanChangeInfo))
+	}
+	if config.CanPostMessages != nil {
+		v.Add("can_post_messages", strconv.FormatBool(*config.CanPostMessages))
+	}
+	if config.CanEditMessages != nil {
+		v.Add("can_edit_messages", strconv.FormatBool(*config.CanEditMessages))
+	}
+	if config.CanDeleteMessages != nil {
+		v.Add("can_delete_messages", strconv.FormatBool(*config.CanDeleteMessages))
+	}
+	if config.CanInviteUsers != nil {
+		v.Add("can_invite_users", strconv.FormatBool(*config.CanInviteUsers))
+	}
+	if config.CanRestrictMembers != nil {
+		v.Add("can_restrict_members", strconv.FormatBool(*config.CanRestrictMembers))
+	}
+	if config.CanPinMessages != nil {
+		v.Add("can_pin_messages", strconv.FormatBool(*config.CanPinMessages))
+	}
+	if config.CanPromoteMembers != nil {
+		v.Add("can_promote_members", strconv.FormatBool(*config.CanPromoteMembers))
+	}
+
+	return v, nil
+}
+
+func (config PromoteChatMemberConfig) method() string {
+	return "promoteChatMember"
+}
+
 // ChatConfig contains information about getting information on a chat.
 type ChatConfig struct {
 	ChatID             int64
 	SuperGroupUsername string
 }
 
+// chatValues returns the chat of ChatConfig as url.Values.
+func (config ChatConfig) chatValues() url.Values {
+	v := url.Values{}
+
+	if config.SuperGroupUsername == "" {
+		v.Add("chat_id", strconv.FormatInt(config.ChatID, 10))
+	} else {
+		v.Add("chat_id", config.SuperGroupUsername)
+	}
+
+	return v
+}
+
+// LeaveChatConfig allows you to leave a chat.
+type LeaveChatConfig struct {
+	ChatConfig
+}
+
+func (config LeaveChatConfig) values() (url.Values, error) {
+	return config.chatValues(), nil
+}
+
+func (config LeaveChatConfig) method() string {
+	return "leaveChat"
+}
+
 // ChatConfigWithUser contains information about getting information on
 // a specific user within a chat.
 type ChatConfigWithUser struct {
 	ChatID             int64
 	SuperGroupUsername string
-	UserID             int
+	UserID             int64
+}
+
+func (config ChatConfigWithUser) values() (url.Values, error) {
+	v := ChatConfig{config.ChatID, config.SuperGroupUsername}.chatValues()
+
+	v.Add("user_id", strconv.FormatInt(config.UserID, 10))
+
+	return v, nil
+}
+
+func (config ChatConfigWithUser) method() string {
+	return "getChatMember"
 }
 
 // InvoiceConfig contains information for sendInvoice request.
@@ -1129,6 +1903,28 @@ type ShippingConfig struct {
 	ErrorMessage    string
 }
 
+func (config ShippingConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	v.Add("shipping_query_id", config.ShippingQueryID)
+	v.Add("ok", strconv.FormatBool(config.OK))
+	if config.OK {
+		data, err := json.Marshal(config.ShippingOptions)
+		if err != nil {
+			return v, err
+		}
+		v.Add("shipping_options", string(data))
+	} else {
+		v.Add("error_message", config.ErrorMessage)
+	}
+
+	return v, nil
+}
+
+func (config ShippingConfig) method() string {
+	return "answerShippingQuery"
+}
+
 // PreCheckoutConfig conatins information for answerPreCheckoutQuery request.
 type PreCheckoutConfig struct {
 	PreCheckoutQueryID string // required
@@ -1136,6 +1932,22 @@ type PreCheckoutConfig struct {
 	ErrorMessage       string
 }
 
+func (config PreCheckoutConfig) values() (url.Values, error) {
+	v := url.Values{}
+
+	v.Add("pre_checkout_query_id", config.PreCheckoutQueryID)
+	v.Add("ok", strconv.FormatBool(config.OK))
+	if !config.OK {
+		v.Add("error", config.ErrorMessage)
+	}
+
+	return v, nil
+}
+
+func (config PreCheckoutConfig) method() string {
+	return "answerPreCheckoutQuery"
+}
+
 // DeleteMessageConfig contains information of a message in a chat to delete.
 type DeleteMessageConfig struct {
 	ChatID    int64
@@ -1236,11 +2048,28 @@ type SetChatPhotoConfig struct {
 	BaseFile
 }
 
+// values returns a url.Values representation of SetChatPhotoConfig.
+func (config SetChatPhotoConfig) values() (url.Values, error) {
+	v, err := config.BaseChat.values()
+	if err != nil {
+		return v, err
+	}
+
+	v.Add(config.name(), config.FileID)
+
+	return v, nil
+}
+
 // name returns the field name for the Photo.
 func (config SetChatPhotoConfig) name() string {
 	return "photo"
 }
 
+// files returns the files to upload for the Photo.
+func (config SetChatPhotoConfig) files() []RequestFile {
+	return config.requestFiles(config.name(), nil)
+}
+
 // method returns Telegram API method name for sending Photo.
 func (config SetChatPhotoConfig) method() string {
 	return "setChatPhoto"
@@ -0,0 +1,479 @@
+package tgbotapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MessageTextBuilder builds the text of a message together with the
+// MessageEntities which format it.
+//
+// Offsets and lengths are counted in UTF-16 code units as Telegram expects,
+// so formatting never depends on a ParseMode or on escaping the text.
+type MessageTextBuilder struct {
+	text     strings.Builder
+	length   int
+	entities []MessageEntity
+}
+
+// NewMessageTextBuilder creates a new, empty MessageTextBuilder.
+func NewMessageTextBuilder() *MessageTextBuilder {
+	return &MessageTextBuilder{}
+}
+
+// Text appends plain, unformatted text.
+func (b *MessageTextBuilder) Text(text string) *MessageTextBuilder {
+	b.text.WriteString(text)
+	b.length += utf16Len(text)
+
+	return b
+}
+
+// Entity appends text formatted by entity. The Offset and Length of the
+// entity are computed automatically.
+func (b *MessageTextBuilder) Entity(text string, entity MessageEntity) *MessageTextBuilder {
+	length := utf16Len(text)
+	if length != 0 {
+		entity.Offset = b.length
+		entity.Length = length
+		b.entities = append(b.entities, entity)
+	}
+
+	b.text.WriteString(text)
+	b.length += length
+
+	return b
+}
+
+// Bold appends bold text.
+func (b *MessageTextBuilder) Bold(text string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityBold})
+}
+
+// Italic appends italic text.
+func (b *MessageTextBuilder) Italic(text string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityItalic})
+}
+
+// Underline appends underlined text.
+func (b *MessageTextBuilder) Underline(text string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityUnderline})
+}
+
+// Strikethrough appends strikethrough text.
+func (b *MessageTextBuilder) Strikethrough(text string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityStrikethrough})
+}
+
+// Spoiler appends text hidden behind a spoiler.
+func (b *MessageTextBuilder) Spoiler(text string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntitySpoiler})
+}
+
+// Code appends inline monowidth text.
+func (b *MessageTextBuilder) Code(text string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityCode})
+}
+
+// Pre appends a monowidth block, language may be empty.
+func (b *MessageTextBuilder) Pre(text, language string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityPre, Language: language})
+}
+
+// TextLink appends text which opens link when clicked.
+func (b *MessageTextBuilder) TextLink(text, link string) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityTextLink, URL: link})
+}
+
+// TextMention appends text which mentions a user without a username.
+func (b *MessageTextBuilder) TextMention(text string, user *User) *MessageTextBuilder {
+	return b.Entity(text, MessageEntity{Type: EntityTextMention, User: user})
+}
+
+// String returns the text built so far.
+func (b *MessageTextBuilder) String() string {
+	return b.text.String()
+}
+
+// Entities returns the entities for the text built so far.
+func (b *MessageTextBuilder) Entities() []MessageEntity {
+	entities := make([]MessageEntity, len(b.entities))
+	copy(entities, b.entities)
+
+	return entities
+}
+
+// Len returns the length of the text built so far in UTF-16 code units.
+func (b *MessageTextBuilder) Len() int {
+	return b.length
+}
+
+// utf16Len returns the number of UTF-16 code units needed to encode s.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
+
+// TextMention is a mention of a user without a username.
+type TextMention struct {
+	Text string
+	User *User
+}
+
+// EntityText returns the part of the message text covered by entity.
+func (m *Message) EntityText(entity MessageEntity) string {
+	return entityText(m.Text, entity)
+}
+
+// EntitiesText returns the text of every entity of entityType in the
+// message text, in the order they appear.
+func (m *Message) EntitiesText(entityType string) []string {
+	var texts []string
+
+	for _, entity := range m.entities() {
+		if entity.Type == entityType {
+			texts = append(texts, m.EntityText(entity))
+		}
+	}
+
+	return texts
+}
+
+// URLs returns all links in the message, both written out in the text
+// and hidden behind text links.
+func (m *Message) URLs() []string {
+	var urls []string
+
+	for _, entity := range m.entities() {
+		switch entity.Type {
+		case EntityURL:
+			urls = append(urls, m.EntityText(entity))
+		case EntityTextLink:
+			urls = append(urls, entity.URL)
+		}
+	}
+
+	return urls
+}
+
+// Mentions returns all @username mentions in the message.
+func (m *Message) Mentions() []string {
+	return m.EntitiesText(EntityMention)
+}
+
+// TextMentions returns all mentions of users without a username.
+func (m *Message) TextMentions() []TextMention {
+	var mentions []TextMention
+
+	for _, entity := range m.entities() {
+		if entity.Type == EntityTextMention {
+			mentions = append(mentions, TextMention{
+				Text: m.EntityText(entity),
+				User: entity.User,
+			})
+		}
+	}
+
+	return mentions
+}
+
+// Hashtags returns all #hashtags in the message.
+func (m *Message) Hashtags() []string {
+	return m.EntitiesText(EntityHashtag)
+}
+
+// Cashtags returns all $USD cashtags in the message.
+func (m *Message) Cashtags() []string {
+	return m.EntitiesText(EntityCashtag)
+}
+
+// BotCommands returns all /commands in the message, including any
+// @botname suffix.
+func (m *Message) BotCommands() []string {
+	return m.EntitiesText(EntityBotCommand)
+}
+
+// TextHTML renders the message text and its entities as HTML suitable for
+// sending with ModeHTML.
+func (m *Message) TextHTML() string {
+	return EntitiesToHTML(m.Text, m.entities())
+}
+
+// TextMarkdownV2 renders the message text and its entities as MarkdownV2
+// suitable for sending with ModeMarkdownV2.
+func (m *Message) TextMarkdownV2() string {
+	return EntitiesToMarkdownV2(m.Text, m.entities())
+}
+
+// CaptionEntityText returns the part of the caption covered by entity.
+func (m *Message) CaptionEntityText(entity MessageEntity) string {
+	return entityText(m.Caption, entity)
+}
+
+// CaptionHTML renders the caption and its entities as HTML suitable for
+// sending with ModeHTML.
+func (m *Message) CaptionHTML() string {
+	if m.CaptionEntities == nil {
+		return EntitiesToHTML(m.Caption, nil)
+	}
+
+	return EntitiesToHTML(m.Caption, *m.CaptionEntities)
+}
+
+// CaptionMarkdownV2 renders the caption and its entities as MarkdownV2
+// suitable for sending with ModeMarkdownV2.
+func (m *Message) CaptionMarkdownV2() string {
+	if m.CaptionEntities == nil {
+		return EntitiesToMarkdownV2(m.Caption, nil)
+	}
+
+	return EntitiesToMarkdownV2(m.Caption, *m.CaptionEntities)
+}
+
+// entities returns the message entities, skipping those with a negative
+// offset or length, or nil if there are none.
+func (m *Message) entities() []MessageEntity {
+	if m.Entities == nil {
+		return nil
+	}
+
+	var entities []MessageEntity
+	for _, entity := range *m.Entities {
+		if validEntity(entity) {
+			entities = append(entities, entity)
+		}
+	}
+
+	return entities
+}
+
+// entityText slices text by the UTF-16 based offset and length of entity.
+// It returns an empty string for an entity with a negative offset or
+// length.
+func entityText(text string, entity MessageEntity) string {
+	if !validEntity(entity) {
+		return ""
+	}
+
+	u := utf16.Encode([]rune(text))
+	start, end := entityBounds(entity, 0, len(u))
+
+	return string(utf16.Decode(u[start:end]))
+}
+
+// validEntity returns if entity has a non-negative offset and length.
+func validEntity(entity MessageEntity) bool {
+	return entity.Offset >= 0 && entity.Length >= 0
+}
+
+// entityBounds returns the start and end of entity, clamped to min and max.
+func entityBounds(entity MessageEntity, min, max int) (int, int) {
+	start, end := entity.Offset, entity.Offset+entity.Length
+	if start < min {
+		start = min
+	}
+	if start > max {
+		start = max
+	}
+	if end < start {
+		end = start
+	}
+	if end > max {
+		end = max
+	}
+
+	return start, end
+}
+
+// EntitiesToHTML renders text formatted by entities as HTML.
+func EntitiesToHTML(text string, entities []MessageEntity) string {
+	return renderEntities(text, entities, htmlFormatter{})
+}
+
+// EntitiesToMarkdownV2 renders text formatted by entities as MarkdownV2.
+func EntitiesToMarkdownV2(text string, entities []MessageEntity) string {
+	return renderEntities(text, entities, markdownV2Formatter{})
+}
+
+// entityFormatter escapes text and wraps it according to an entity.
+type entityFormatter interface {
+	escape(text string, code bool) string
+	format(entity MessageEntity, inner string) string
+}
+
+// renderEntities renders text with entities applied through formatter.
+// Nested entities are supported. An entity starting inside another one
+// but ending after it is cut off at the end of the other one. Entities
+// with a negative offset or length are skipped.
+func renderEntities(text string, entities []MessageEntity, formatter entityFormatter) string {
+	sorted := make([]MessageEntity, 0, len(entities))
+	for _, entity := range entities {
+		if validEntity(entity) {
+			sorted = append(sorted, entity)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Offset == sorted[j].Offset {
+			return sorted[i].Length > sorted[j].Length
+		}
+		return sorted[i].Offset < sorted[j].Offset
+	})
+
+	u := utf16.Encode([]rune(text))
+
+	return renderRange(u, 0, len(u), sorted, formatter)
+}
+
+func renderRange(u []uint16, start, end int, entities []MessageEntity, formatter entityFormatter) string {
+	var b strings.Builder
+
+	pos := start
+	for i := 0; i < len(entities); {
+		entity := entities[i]
+		entityStart, entityEnd := entityBounds(entity, start, end)
+		if entityStart < pos {
+			i++
+			continue
+		}
+
+		// every following entity starting inside this one is nested in it
+		j := i + 1
+		for j < len(entities) && entities[j].Offset < entityEnd {
+			j++
+		}
+
+		var inner string
+		if entity.Type == EntityCode || entity.Type == EntityPre {
+			inner = formatter.escape(string(utf16.Decode(u[entityStart:entityEnd])), true)
+		} else {
+			inner = renderRange(u, entityStart, entityEnd, entities[i+1:j], formatter)
+		}
+
+		b.WriteString(formatter.escape(string(utf16.Decode(u[pos:entityStart])), false))
+		b.WriteString(formatter.format(entity, inner))
+
+		pos = entityEnd
+		i = j
+	}
+
+	b.WriteString(formatter.escape(string(utf16.Decode(u[pos:end])), false))
+
+	return b.String()
+}
+
+type htmlFormatter struct{}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func (htmlFormatter) escape(text string, code bool) string {
+	return htmlEscaper.Replace(text)
+}
+
+func (htmlFormatter) format(entity MessageEntity, inner string) string {
+	switch entity.Type {
+	case EntityBold:
+		return "<b>" + inner + "</b>"
+	case EntityItalic:
+		return "<i>" + inner + "</i>"
+	case EntityUnderline:
+		return "<u>" + inner + "</u>"
+	case EntityStrikethrough:
+		return "<s>" + inner + "</s>"
+	case EntitySpoiler:
+		return "<tg-spoiler>" + inner + "</tg-spoiler>"
+	case EntityCode:
+		return "<code>" + inner + "</code>"
+	case EntityPre:
+		if entity.Language != "" {
+			return `<pre><code class="language-` + htmlEscaper.Replace(entity.Language) + `">` + inner + "</code></pre>"
+		}
+		return "<pre>" + inner + "</pre>"
+	case EntityTextLink:
+		return `<a href="` + htmlEscaper.Replace(entity.URL) + `">` + inner + "</a>"
+	case EntityTextMention:
+		if entity.User != nil {
+			return `<a href="tg://user?id=` + strconv.FormatInt(entity.User.ID, 10) + `">` + inner + "</a>"
+		}
+	}
+
+	return inner
+}
+
+type markdownV2Formatter struct{}
+
+var (
+	markdownV2Escaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`,
+		")", `\)`, "~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`,
+		"-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`,
+		"!", `\!`,
+	)
+	markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	markdownV2LinkEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+func (markdownV2Formatter) escape(text string, code bool) string {
+	if code {
+		return markdownV2CodeEscaper.Replace(text)
+	}
+
+	return markdownV2Escaper.Replace(text)
+}
+
+func (markdownV2Formatter) format(entity MessageEntity, inner string) string {
+	switch entity.Type {
+	case EntityBold:
+		return "*" + inner + "*"
+	case EntityItalic:
+		return markdownV2Underscores("_", inner)
+	case EntityUnderline:
+		return markdownV2Underscores("__", inner)
+	case EntityStrikethrough:
+		return "~" + inner + "~"
+	case EntitySpoiler:
+		return "||" + inner + "||"
+	case EntityCode:
+		return "`" + inner + "`"
+	case EntityPre:
+		language := entity.Language
+		if strings.ContainsAny(language, "`\\ \t\r\n") {
+			// The language can not be escaped and is only a hint.
+			language = ""
+		}
+		return "```" + language + "\n" + inner + "\n```"
+	case EntityTextLink:
+		return "[" + inner + "](" + markdownV2LinkEscaper.Replace(entity.URL) + ")"
+	case EntityTextMention:
+		if entity.User != nil {
+			return "[" + inner + "](tg://user?id=" + strconv.FormatInt(entity.User.ID, 10) + ")"
+		}
+	}
+
+	return inner
+}
+
+// markdownV2Underscores wraps inner in delimiter, which is "_" for italic
+// or "__" for underline text. Telegram reads runs of underscores greedily
+// as underline delimiters, so a carriage return, which Telegram ignores,
+// separates delimiter from the delimiters of nested entities.
+func markdownV2Underscores(delimiter, inner string) string {
+	start, end := delimiter, delimiter
+	if strings.HasPrefix(inner, "_") {
+		start += "\r"
+	}
+	if strings.HasSuffix(inner, "_") {
+		end = "\r" + end
+	}
+
+	return start + inner + end
+}
@@ -0,0 +1,181 @@
+package tgbotapi_test
+
+import (
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestMessageTextBuilderOffsets(t *testing.T) {
+	b := tgbotapi.NewMessageTextBuilder().
+		Text("Hi 👋 ").
+		Bold("bold").
+		Text(" and ").
+		TextLink("link", "https://example.com")
+
+	if b.String() != "Hi 👋 bold and link" {
+		t.Error(b.String())
+	}
+
+	entities := b.Entities()
+	if len(entities) != 2 {
+		t.Fatal(entities)
+	}
+
+	// the emoji takes two UTF-16 code units
+	if entities[0].Type != "bold" || entities[0].Offset != 6 || entities[0].Length != 4 {
+		t.Error(entities[0])
+	}
+
+	if entities[1].Type != "text_link" || entities[1].Offset != 15 || entities[1].Length != 4 ||
+		entities[1].URL != "https://example.com" {
+		t.Error(entities[1])
+	}
+
+	if b.Len() != 19 {
+		t.Error(b.Len())
+	}
+}
+
+func TestMessageTextBuilderSkipsEmptyEntities(t *testing.T) {
+	b := tgbotapi.NewMessageTextBuilder().Bold("").Text("text")
+
+	if len(b.Entities()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestNewMessageWithEntities(t *testing.T) {
+	b := tgbotapi.NewMessageTextBuilder().Pre("fmt.Println()", "go")
+	msg := tgbotapi.NewMessageWithEntities(ChatID, b.String(), b.Entities())
+
+	if msg.ChatID != ChatID ||
+		msg.Text != "fmt.Println()" ||
+		msg.ParseMode != "" ||
+		len(msg.Entities) != 1 ||
+		msg.Entities[0].Language != "go" {
+		t.Fail()
+	}
+}
+
+func TestMessageEntityTextWithEmoji(t *testing.T) {
+	message := tgbotapi.Message{Text: "👍 #go costs $USD at https://golang.org"}
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "hashtag", Offset: 3, Length: 3},
+		{Type: "cashtag", Offset: 13, Length: 4},
+		{Type: "url", Offset: 21, Length: 18},
+	}
+
+	if tags := message.Hashtags(); len(tags) != 1 || tags[0] != "#go" {
+		t.Error(tags)
+	}
+	if tags := message.Cashtags(); len(tags) != 1 || tags[0] != "$USD" {
+		t.Error(tags)
+	}
+	if urls := message.URLs(); len(urls) != 1 || urls[0] != "https://golang.org" {
+		t.Error(urls)
+	}
+}
+
+func TestMessageTextMentions(t *testing.T) {
+	user := &tgbotapi.User{ID: 10, FirstName: "Test"}
+	message := tgbotapi.Message{Text: "hi Test and @test"}
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "text_mention", Offset: 3, Length: 4, User: user},
+		{Type: "mention", Offset: 12, Length: 5},
+	}
+
+	mentions := message.TextMentions()
+	if len(mentions) != 1 || mentions[0].Text != "Test" || mentions[0].User != user {
+		t.Error(mentions)
+	}
+	if m := message.Mentions(); len(m) != 1 || m[0] != "@test" {
+		t.Error(m)
+	}
+}
+
+func TestMessageTextHTML(t *testing.T) {
+	message := tgbotapi.Message{Text: "😀 bold italic <tag> code"}
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "bold", Offset: 3, Length: 11},
+		{Type: "italic", Offset: 8, Length: 6},
+		{Type: "code", Offset: 21, Length: 4},
+	}
+
+	expected := "😀 <b>bold <i>italic</i></b> &lt;tag&gt; <code>code</code>"
+	if html := message.TextHTML(); html != expected {
+		t.Error(html)
+	}
+}
+
+func TestMessageTextMarkdownV2(t *testing.T) {
+	message := tgbotapi.Message{Text: "Go 1.x is here, see docs!"}
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "bold", Offset: 0, Length: 6},
+		{Type: "text_link", Offset: 20, Length: 4, URL: "https://golang.org/doc"},
+	}
+
+	expected := `*Go 1\.x* is here, see [docs](https://golang.org/doc)\!`
+	if md := message.TextMarkdownV2(); md != expected {
+		t.Error(md)
+	}
+}
+
+func TestMessageTextMarkdownV2ItalicUnderline(t *testing.T) {
+	message := tgbotapi.Message{Text: "italic underline"}
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "underline", Offset: 0, Length: 16},
+		{Type: "italic", Offset: 0, Length: 16},
+	}
+
+	expected := "__\r_italic underline_\r__"
+	if md := message.TextMarkdownV2(); md != expected {
+		t.Errorf("%q", md)
+	}
+
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "italic", Offset: 0, Length: 16},
+		{Type: "underline", Offset: 7, Length: 9},
+	}
+
+	expected = "_italic __underline__\r_"
+	if md := message.TextMarkdownV2(); md != expected {
+		t.Errorf("%q", md)
+	}
+}
+
+func TestMessageTextMarkdownV2PreLanguage(t *testing.T) {
+	message := tgbotapi.Message{Text: "fmt.Println()"}
+
+	for language, expected := range map[string]string{
+		"go":         "```go\nfmt.Println()\n```",
+		"go`\n` x":   "```\nfmt.Println()\n```",
+		"go\nrm -rf": "```\nfmt.Println()\n```",
+	} {
+		message.Entities = &[]tgbotapi.MessageEntity{{Type: "pre", Offset: 0, Length: 13, Language: language}}
+
+		if md := message.TextMarkdownV2(); md != expected {
+			t.Errorf("%q: %q", language, md)
+		}
+	}
+}
+
+func TestMessageTextInvalidEntities(t *testing.T) {
+	message := tgbotapi.Message{Text: "bold italic #tag"}
+	message.Entities = &[]tgbotapi.MessageEntity{
+		{Type: "bold", Offset: -5, Length: 2},
+		{Type: "hashtag", Offset: 12, Length: -4},
+		{Type: "bold", Offset: 0, Length: 8},
+		{Type: "italic", Offset: 5, Length: 6},
+	}
+
+	if tags := message.Hashtags(); len(tags) != 0 {
+		t.Error(tags)
+	}
+
+	// The italic entity is cut off at the end of the bold one.
+	expected := "<b>bold <i>ita</i></b>lic #tag"
+	if html := message.TextHTML(); html != expected {
+		t.Error(html)
+	}
+}
@@ -0,0 +1,224 @@
+package tgbotapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileIDCache remembers the file IDs Telegram assigned to uploaded content,
+// so the same content does not have to be uploaded again.
+//
+// File IDs are only valid for the bot which uploaded the file, so a cache
+// must not be shared between bots.
+type FileIDCache interface {
+	// Get returns the file ID stored for key.
+	Get(key string) (fileID string, ok bool)
+	// Set stores fileID for key.
+	Set(key, fileID string) error
+	// Delete removes key, for example when its file ID became invalid.
+	Delete(key string) error
+}
+
+// MemoryFileIDCache is a FileIDCache which keeps file IDs in memory.
+type MemoryFileIDCache struct {
+	mu      sync.RWMutex
+	fileIDs map[string]string
+}
+
+// NewMemoryFileIDCache creates a new, empty MemoryFileIDCache.
+func NewMemoryFileIDCache() *MemoryFileIDCache {
+	return &MemoryFileIDCache{
+		fileIDs: make(map[string]string),
+	}
+}
+
+// Get returns the file ID stored for key.
+func (c *MemoryFileIDCache) Get(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	fileID, ok := c.fileIDs[key]
+	return fileID, ok
+}
+
+// Set stores fileID for key.
+func (c *MemoryFileIDCache) Set(key, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fileIDs[key] = fileID
+	return nil
+}
+
+// Delete removes key.
+func (c *MemoryFileIDCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.fileIDs, key)
+	return nil
+}
+
+// DiskFileIDCache is a FileIDCache which keeps file IDs in memory and
+// writes them to a JSON file on every change, so they survive restarts.
+type DiskFileIDCache struct {
+	MemoryFileIDCache
+	path string
+}
+
+// NewDiskFileIDCache creates a DiskFileIDCache stored at path and loads
+// the file IDs already stored there, if any.
+func NewDiskFileIDCache(path string) (*DiskFileIDCache, error) {
+	c := &DiskFileIDCache{
+		MemoryFileIDCache: MemoryFileIDCache{fileIDs: make(map[string]string)},
+		path:              path,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c.fileIDs); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Set stores fileID for key and saves the cache.
+func (c *DiskFileIDCache) Set(key, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fileIDs[key] = fileID
+	return c.save()
+}
+
+// Delete removes key and saves the cache.
+func (c *DiskFileIDCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.fileIDs, key)
+	return c.save()
+}
+
+// save atomically replaces the cache file. c.mu must be held.
+func (c *DiskFileIDCache) save() error {
+	data, err := json.Marshal(c.fileIDs)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it to path, so path never contains partial data.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+
+	return err
+}
+
+// fileIDCacheKey returns the key to cache the file ID of file uploaded as
+// the field name. It returns an empty key if file can not be cached, such
+// as a FileReader which can only be read once.
+func fileIDCacheKey(name string, file interface{}) string {
+	hash := sha256.New()
+
+	switch f := file.(type) {
+	case string:
+		if !hashFile(hash, f) {
+			return ""
+		}
+	case FilePath:
+		if !hashFile(hash, string(f)) {
+			return ""
+		}
+	case FileBytes:
+		hash.Write(f.Bytes)
+	default:
+		return ""
+	}
+
+	return name + ":" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashFile writes the content of the file at path to w.
+func hashFile(w io.Writer, path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err == nil
+}
+
+// messageFileID returns the ID of the file sent in message as the field
+// name.
+func messageFileID(message Message, name string) string {
+	switch name {
+	case "photo":
+		if message.Photo != nil && len(*message.Photo) > 0 {
+			photos := *message.Photo
+			return photos[len(photos)-1].FileID
+		}
+	case "audio":
+		if message.Audio != nil {
+			return message.Audio.FileID
+		}
+	case "document":
+		if message.Document != nil {
+			return message.Document.FileID
+		}
+	case "sticker":
+		if message.Sticker != nil {
+			return message.Sticker.FileID
+		}
+	case "video":
+		if message.Video != nil {
+			return message.Video.FileID
+		}
+	case "animation":
+		if message.Animation != nil {
+			return message.Animation.FileID
+		}
+	case "video_note":
+		if message.VideoNote != nil {
+			return message.VideoNote.FileID
+		}
+	case "voice":
+		if message.Voice != nil {
+			return message.Voice.FileID
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,107 @@
+package tgbotapi_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestDiskFileIDCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tgbotapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cachePath := path.Join(dir, "cache.json")
+
+	cache, err := tgbotapi.NewDiskFileIDCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("a", "file-a")
+	cache.Set("b", "file-b")
+	cache.Delete("b")
+
+	cache, err = tgbotapi.NewDiskFileIDCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fileID, ok := cache.Get("a"); !ok || fileID != "file-a" {
+		t.Error(fileID, ok)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Error("deleted key was loaded")
+	}
+}
+
+func TestSendUsesFileIDCache(t *testing.T) {
+	uploads := 0
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+			uploads++
+		} else {
+			req.ParseForm()
+			if req.FormValue("document") != "cached-id" {
+				t.Errorf("expected the cached file ID, got %q", req.FormValue("document"))
+			}
+		}
+
+		return tgbotapi.Message{MessageID: 1, Document: &tgbotapi.Document{FileID: "cached-id"}}
+	})
+	bot.FileIDCache = tgbotapi.NewMemoryFileIDCache()
+
+	for i := 0; i < 3; i++ {
+		cfg := tgbotapi.NewDocumentUpload(ChatID, tgbotapi.FileBytes{Name: "doc.txt", Bytes: []byte("content")})
+		if _, err := bot.Send(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if uploads != 1 {
+		t.Errorf("expected 1 upload, got %d", uploads)
+	}
+
+	cfg := tgbotapi.NewDocumentUpload(ChatID, tgbotapi.FileBytes{Name: "doc.txt", Bytes: []byte("other")})
+	if _, err := bot.Send(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if uploads != 2 {
+		t.Errorf("different content must be uploaded, got %d uploads", uploads)
+	}
+}
+
+// failingFileIDCache is a FileIDCache which can not store file IDs.
+type failingFileIDCache struct{}
+
+func (failingFileIDCache) Get(key string) (string, bool) { return "", false }
+func (failingFileIDCache) Set(key, fileID string) error  { return errors.New("disk full") }
+func (failingFileIDCache) Delete(key string) error       { return errors.New("disk full") }
+
+func TestSendLogsFileIDCacheErrors(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return tgbotapi.Message{MessageID: 1, Document: &tgbotapi.Document{FileID: "id"}}
+	})
+	logger := &recordingLogger{}
+	bot.Logger = logger
+	bot.FileIDCache = failingFileIDCache{}
+
+	cfg := tgbotapi.NewDocumentUpload(ChatID, tgbotapi.FileBytes{Name: "doc.txt", Bytes: []byte("content")})
+	if _, err := bot.Send(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := logger.out.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "disk full") {
+		t.Errorf("expected the cache error to be logged, got %q", out)
+	}
+}
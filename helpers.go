@@ -18,6 +18,21 @@ func NewMessage(chatID int64, text string) MessageConfig {
 	}
 }
 
+// NewMessageWithEntities creates a new Message formatted by entities
+// instead of a ParseMode.
+//
+// chatID is where to send it, text is the message text and entities
+// are usually created with a MessageTextBuilder.
+func NewMessageWithEntities(chatID int64, text string, entities []MessageEntity) MessageConfig {
+	return MessageConfig{
+		BaseChat: BaseChat{
+			ChatID: chatID,
+		},
+		Text:     text,
+		Entities: entities,
+	}
+}
+
 // NewDeleteMessage creates a request to delete a message.
 func NewDeleteMessage(chatID int64, messageID int) DeleteMessageConfig {
 	return DeleteMessageConfig{
@@ -291,7 +306,8 @@ func NewVoiceShare(chatID int64, fileID string) VoiceConfig {
 }
 
 // NewMediaGroup creates a new media group. Files should be an array of
-// two to ten InputMediaPhoto or InputMediaVideo.
+// two to ten InputMediaPhoto and InputMediaVideo, or only InputMediaAudio,
+// or only InputMediaDocument.
 func NewMediaGroup(chatID int64, files []interface{}) MediaGroupConfig {
 	return MediaGroupConfig{
 		BaseChat: BaseChat{
@@ -302,7 +318,10 @@ func NewMediaGroup(chatID int64, files []interface{}) MediaGroupConfig {
 }
 
 // NewInputMediaPhoto creates a new InputMediaPhoto.
-func NewInputMediaPhoto(media string) InputMediaPhoto {
+//
+// media is a file ID or URL string, or a FilePath, FileBytes or
+// FileReader to upload.
+func NewInputMediaPhoto(media interface{}) InputMediaPhoto {
 	return InputMediaPhoto{
 		Type:  "photo",
 		Media: media,
@@ -310,13 +329,49 @@ func NewInputMediaPhoto(media string) InputMediaPhoto {
 }
 
 // NewInputMediaVideo creates a new InputMediaVideo.
-func NewInputMediaVideo(media string) InputMediaVideo {
+//
+// media is a file ID or URL string, or a FilePath, FileBytes or
+// FileReader to upload.
+func NewInputMediaVideo(media interface{}) InputMediaVideo {
 	return InputMediaVideo{
 		Type:  "video",
 		Media: media,
 	}
 }
 
+// NewInputMediaAnimation creates a new InputMediaAnimation.
+//
+// media is a file ID or URL string, or a FilePath, FileBytes or
+// FileReader to upload.
+func NewInputMediaAnimation(media interface{}) InputMediaAnimation {
+	return InputMediaAnimation{
+		Type:  "animation",
+		Media: media,
+	}
+}
+
+// NewInputMediaAudio creates a new InputMediaAudio.
+//
+// media is a file ID or URL string, or a FilePath, FileBytes or
+// FileReader to upload.
+func NewInputMediaAudio(media interface{}) InputMediaAudio {
+	return InputMediaAudio{
+		Type:  "audio",
+		Media: media,
+	}
+}
+
+// NewInputMediaDocument creates a new InputMediaDocument.
+//
+// media is a file ID or URL string, or a FilePath, FileBytes or
+// FileReader to upload.
+func NewInputMediaDocument(media interface{}) InputMediaDocument {
+	return InputMediaDocument{
+		Type:  "document",
+		Media: media,
+	}
+}
+
 // NewContact allows you to send a shared contact.
 func NewContact(chatID int64, phoneNumber, firstName string) ContactConfig {
 	return ContactConfig{
@@ -341,6 +396,41 @@ func NewLocation(chatID int64, latitude float64, longitude float64) LocationConf
 	}
 }
 
+// NewLiveLocation shares a live location, which can be updated for
+// livePeriod seconds.
+func NewLiveLocation(chatID int64, latitude, longitude float64, livePeriod int) LocationConfig {
+	return LocationConfig{
+		BaseChat: BaseChat{
+			ChatID: chatID,
+		},
+		Latitude:   latitude,
+		Longitude:  longitude,
+		LivePeriod: livePeriod,
+	}
+}
+
+// NewEditMessageLiveLocation allows you to update a live location.
+func NewEditMessageLiveLocation(chatID int64, messageID int, latitude, longitude float64) EditMessageLiveLocationConfig {
+	return EditMessageLiveLocationConfig{
+		BaseEdit: BaseEdit{
+			ChatID:    chatID,
+			MessageID: messageID,
+		},
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
+}
+
+// NewStopMessageLiveLocation allows you to stop updating a live location.
+func NewStopMessageLiveLocation(chatID int64, messageID int) StopMessageLiveLocationConfig {
+	return StopMessageLiveLocationConfig{
+		BaseEdit: BaseEdit{
+			ChatID:    chatID,
+			MessageID: messageID,
+		},
+	}
+}
+
 // NewVenue allows you to send a venue and its location.
 func NewVenue(chatID int64, title, address string, latitude, longitude float64) VenueConfig {
 	return VenueConfig{
@@ -368,7 +458,7 @@ func NewChatAction(chatID int64, action string) ChatActionConfig {
 // NewUserProfilePhotos gets user profile photos.
 //
 // userID is the ID of the user you wish to get profile photos from.
-func NewUserProfilePhotos(userID int) UserProfilePhotosConfig {
+func NewUserProfilePhotos(userID int64) UserProfilePhotosConfig {
 	return UserProfilePhotosConfig{
 		UserID: userID,
 		Offset: 0,
@@ -604,6 +694,96 @@ func NewInlineQueryResultLocation(id, title string, latitude, longitude float64)
 	}
 }
 
+// NewInlineQueryResultVenue creates a new inline query venue.
+func NewInlineQueryResultVenue(id, title, address string, latitude, longitude float64) InlineQueryResultVenue {
+	return InlineQueryResultVenue{
+		Type:      "venue",
+		ID:        id,
+		Title:     title,
+		Address:   address,
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
+}
+
+// NewInlineQueryResultContact creates a new inline query contact.
+func NewInlineQueryResultContact(id, phoneNumber, firstName string) InlineQueryResultContact {
+	return InlineQueryResultContact{
+		Type:        "contact",
+		ID:          id,
+		PhoneNumber: phoneNumber,
+		FirstName:   firstName,
+	}
+}
+
+// NewInlineQueryResultCachedSticker create a new inline query with cached sticker.
+func NewInlineQueryResultCachedSticker(id, stickerID string) InlineQueryResultCachedSticker {
+	return InlineQueryResultCachedSticker{
+		Type:      "sticker",
+		ID:        id,
+		StickerID: stickerID,
+	}
+}
+
+// NewInlineQueryResultGame creates a new inline query game.
+func NewInlineQueryResultGame(id, gameShortName string) InlineQueryResultGame {
+	return InlineQueryResultGame{
+		Type:          "game",
+		ID:            id,
+		GameShortName: gameShortName,
+	}
+}
+
+// NewInputTextMessageContent creates the content of a text message sent as
+// the result of an inline query.
+func NewInputTextMessageContent(text string) InputTextMessageContent {
+	return InputTextMessageContent{
+		Text: text,
+	}
+}
+
+// NewInputLocationMessageContent creates the content of a location sent as
+// the result of an inline query.
+func NewInputLocationMessageContent(latitude, longitude float64) InputLocationMessageContent {
+	return InputLocationMessageContent{
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
+}
+
+// NewInputVenueMessageContent creates the content of a venue sent as the
+// result of an inline query.
+func NewInputVenueMessageContent(title, address string, latitude, longitude float64) InputVenueMessageContent {
+	return InputVenueMessageContent{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Title:     title,
+		Address:   address,
+	}
+}
+
+// NewInputContactMessageContent creates the content of a contact sent as
+// the result of an inline query.
+func NewInputContactMessageContent(phoneNumber, firstName string) InputContactMessageContent {
+	return InputContactMessageContent{
+		PhoneNumber: phoneNumber,
+		FirstName:   firstName,
+	}
+}
+
+// NewInputInvoiceMessageContent creates the content of an invoice sent as
+// the result of an inline query.
+func NewInputInvoiceMessageContent(title, description, payload, providerToken, currency string, prices []LabeledPrice) InputInvoiceMessageContent {
+	return InputInvoiceMessageContent{
+		Title:         title,
+		Description:   description,
+		Payload:       payload,
+		ProviderToken: providerToken,
+		Currency:      currency,
+		Prices:        prices,
+	}
+}
+
 // NewEditMessageText allows you to edit the text of a message.
 func NewEditMessageText(chatID int64, messageID int, text string) EditMessageTextConfig {
 	return EditMessageTextConfig{
@@ -622,7 +802,7 @@ func NewEditMessageCaption(chatID int64, messageID int, caption string) EditMess
 			ChatID:    chatID,
 			MessageID: messageID,
 		},
-		Caption:   caption,
+		Caption: caption,
 	}
 }
 
@@ -638,6 +818,51 @@ func NewEditMessageReplyMarkup(chatID int64, messageID int, replyMarkup InlineKe
 	}
 }
 
+// NewEditMessageMedia allows you to replace the media of a message with
+// media, such as an InputMediaPhoto.
+func NewEditMessageMedia(chatID int64, messageID int, media interface{}) EditMessageMediaConfig {
+	return EditMessageMediaConfig{
+		BaseEdit: BaseEdit{
+			ChatID:    chatID,
+			MessageID: messageID,
+		},
+		Media: media,
+	}
+}
+
+// NewEditMessageTextInline allows you to edit the text of a message sent
+// via the bot in inline mode.
+func NewEditMessageTextInline(inlineMessageID, text string) EditMessageTextConfig {
+	return EditMessageTextConfig{
+		BaseEdit: BaseEdit{
+			InlineMessageID: inlineMessageID,
+		},
+		Text: text,
+	}
+}
+
+// NewEditMessageCaptionInline allows you to edit the caption of a message
+// sent via the bot in inline mode.
+func NewEditMessageCaptionInline(inlineMessageID, caption string) EditMessageCaptionConfig {
+	return EditMessageCaptionConfig{
+		BaseEdit: BaseEdit{
+			InlineMessageID: inlineMessageID,
+		},
+		Caption: caption,
+	}
+}
+
+// NewEditMessageReplyMarkupInline allows you to edit the inline keyboard
+// markup of a message sent via the bot in inline mode.
+func NewEditMessageReplyMarkupInline(inlineMessageID string, replyMarkup InlineKeyboardMarkup) EditMessageReplyMarkupConfig {
+	return EditMessageReplyMarkupConfig{
+		BaseEdit: BaseEdit{
+			InlineMessageID: inlineMessageID,
+			ReplyMarkup:     &replyMarkup,
+		},
+	}
+}
+
 // NewHideKeyboard hides the keyboard, with the option for being selective
 // or hiding for everyone.
 func NewHideKeyboard(selective bool) ReplyKeyboardHide {
@@ -683,6 +908,47 @@ func NewKeyboardButtonLocation(text string) KeyboardButton {
 	}
 }
 
+// NewKeyboardButtonPoll creates a keyboard button that asks the user to
+// create a poll of pollType, "quiz" or "regular", or any type if empty.
+func NewKeyboardButtonPoll(text, pollType string) KeyboardButton {
+	return KeyboardButton{
+		Text:        text,
+		RequestPoll: &KeyboardButtonPollType{Type: pollType},
+	}
+}
+
+// NewKeyboardButtonRequestUser creates a keyboard button that asks the
+// user to select a user. requestID identifies the request in the
+// resulting message.
+func NewKeyboardButtonRequestUser(text string, requestID int) KeyboardButton {
+	return KeyboardButton{
+		Text:        text,
+		RequestUser: &KeyboardButtonRequestUser{RequestID: requestID},
+	}
+}
+
+// NewKeyboardButtonRequestChat creates a keyboard button that asks the
+// user to select a group, or a channel if chatIsChannel is set. requestID
+// identifies the request in the resulting message.
+func NewKeyboardButtonRequestChat(text string, requestID int, chatIsChannel bool) KeyboardButton {
+	return KeyboardButton{
+		Text: text,
+		RequestChat: &KeyboardButtonRequestChat{
+			RequestID:     requestID,
+			ChatIsChannel: chatIsChannel,
+		},
+	}
+}
+
+// NewKeyboardButtonWebApp creates a keyboard button that opens the Web App
+// at url.
+func NewKeyboardButtonWebApp(text, url string) KeyboardButton {
+	return KeyboardButton{
+		Text:   text,
+		WebApp: &WebAppInfo{URL: url},
+	}
+}
+
 // NewKeyboardButtonRow creates a row of keyboard buttons.
 func NewKeyboardButtonRow(buttons ...KeyboardButton) []KeyboardButton {
 	var row []KeyboardButton
@@ -704,6 +970,25 @@ func NewReplyKeyboard(rows ...[]KeyboardButton) ReplyKeyboardMarkup {
 	}
 }
 
+// NewOneTimeReplyKeyboard creates a new regular keyboard which is hidden
+// after it was used.
+func NewOneTimeReplyKeyboard(rows ...[]KeyboardButton) ReplyKeyboardMarkup {
+	markup := NewReplyKeyboard(rows...)
+	markup.OneTimeKeyboard = true
+
+	return markup
+}
+
+// NewForceReply forces the user to reply to the message, showing
+// placeholder in the input field if it is not empty.
+func NewForceReply(placeholder string, selective bool) ForceReply {
+	return ForceReply{
+		ForceReply:            true,
+		InputFieldPlaceholder: placeholder,
+		Selective:             selective,
+	}
+}
+
 // NewInlineKeyboardButtonData creates an inline keyboard button with text
 // and data for a callback.
 func NewInlineKeyboardButtonData(text, data string) InlineKeyboardButton {
@@ -731,6 +1016,44 @@ func NewInlineKeyboardButtonSwitch(text, sw string) InlineKeyboardButton {
 	}
 }
 
+// NewInlineKeyboardButtonSwitchCurrentChat creates an inline keyboard
+// button with text which starts an inline query with sw in the current
+// chat.
+func NewInlineKeyboardButtonSwitchCurrentChat(text, sw string) InlineKeyboardButton {
+	return InlineKeyboardButton{
+		Text:                         text,
+		SwitchInlineQueryCurrentChat: &sw,
+	}
+}
+
+// NewInlineKeyboardButtonSwitchChosenChat creates an inline keyboard
+// button with text which lets the user choose a chat to start an inline
+// query in.
+func NewInlineKeyboardButtonSwitchChosenChat(text string, chosenChat SwitchInlineQueryChosenChat) InlineKeyboardButton {
+	return InlineKeyboardButton{
+		Text:                        text,
+		SwitchInlineQueryChosenChat: &chosenChat,
+	}
+}
+
+// NewInlineKeyboardButtonLoginURL creates an inline keyboard button with
+// text which logs the user in to the website of loginURL.
+func NewInlineKeyboardButtonLoginURL(text string, loginURL LoginURL) InlineKeyboardButton {
+	return InlineKeyboardButton{
+		Text:     text,
+		LoginURL: &loginURL,
+	}
+}
+
+// NewInlineKeyboardButtonWebApp creates an inline keyboard button with
+// text which opens the Web App at url.
+func NewInlineKeyboardButtonWebApp(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{
+		Text:   text,
+		WebApp: &WebAppInfo{URL: url},
+	}
+}
+
 // NewInlineKeyboardRow creates an inline keyboard row with buttons.
 func NewInlineKeyboardRow(buttons ...InlineKeyboardButton) []InlineKeyboardButton {
 	var row []InlineKeyboardButton
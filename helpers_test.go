@@ -175,3 +175,57 @@ func TestNewEditMessageReplyMarkup(t *testing.T) {
 	}
 
 }
+
+func TestNewInlineQueryResultVenue(t *testing.T) {
+	result := tgbotapi.NewInlineQueryResultVenue("id", "title", "address", 50.0, 30.0)
+
+	if result.Type != "venue" ||
+		result.ResultType() != "venue" ||
+		result.ResultID() != "id" ||
+		result.Title != "title" ||
+		result.Address != "address" ||
+		result.Latitude != 50.0 ||
+		result.Longitude != 30.0 {
+		t.Fail()
+	}
+}
+
+func TestNewInlineQueryResultContact(t *testing.T) {
+	result := tgbotapi.NewInlineQueryResultContact("id", "+1234", "name")
+
+	if result.Type != "contact" ||
+		result.ID != "id" ||
+		result.PhoneNumber != "+1234" ||
+		result.FirstName != "name" {
+		t.Fail()
+	}
+}
+
+func TestNewInlineQueryResultCachedSticker(t *testing.T) {
+	result := tgbotapi.NewInlineQueryResultCachedSticker("id", "sticker")
+
+	if result.Type != "sticker" ||
+		result.ID != "id" ||
+		result.StickerID != "sticker" {
+		t.Fail()
+	}
+}
+
+func TestNewInputMessageContent(t *testing.T) {
+	text := tgbotapi.NewInputTextMessageContent("message")
+	location := tgbotapi.NewInputLocationMessageContent(50.0, 30.0)
+	venue := tgbotapi.NewInputVenueMessageContent("title", "address", 50.0, 30.0)
+	contact := tgbotapi.NewInputContactMessageContent("+1234", "name")
+
+	if text.Text != "message" ||
+		location.Latitude != 50.0 ||
+		location.Longitude != 30.0 ||
+		venue.Title != "title" ||
+		venue.Address != "address" ||
+		venue.Latitude != 50.0 ||
+		venue.Longitude != 30.0 ||
+		contact.PhoneNumber != "+1234" ||
+		contact.FirstName != "name" {
+		t.Fail()
+	}
+}
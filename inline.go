@@ -0,0 +1,296 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxInlineQueryResults is the largest number of results Telegram accepts
+// in a single answer to an inline query.
+const MaxInlineQueryResults = 50
+
+// MaxInlineResultIDLength is the largest ID of an inline query result in
+// bytes.
+const MaxInlineResultIDLength = 64
+
+// DefaultInlineQueryDeadline is how long an InlineQueryHandler has to
+// answer a query if it has no Deadline. Telegram rejects answers after 30
+// seconds, so some time is left for the request itself.
+const DefaultInlineQueryDeadline = 25 * time.Second
+
+// InlineQuerySource returns all results for query.
+//
+// ctx is done once the deadline to answer the query passed. The query is
+// not answered then, and results returned later are discarded.
+type InlineQuerySource func(ctx context.Context, query *InlineQuery) ([]InlineQueryResult, error)
+
+// minInlineCachePrune is the number of cached queries from which an
+// InlineQueryHandler starts removing expired ones.
+const minInlineCachePrune = 64
+
+// inlineCacheEntry are the results of a query cached for a user.
+type inlineCacheEntry struct {
+	results []InlineQueryResult
+	expires time.Time
+}
+
+// inlineSourceResult is what an InlineQuerySource returned.
+type inlineSourceResult struct {
+	results []InlineQueryResult
+	err     error
+}
+
+// InlineQueryHandler answers inline queries with the results of Source,
+// one page at a time.
+//
+// Telegram requests further pages as the user scrolls, passing the
+// NextOffset of the previous answer as the Offset of the query. If
+// CacheTTL is set, the results are cached per user and query in the
+// meantime, so Source is only called once.
+type InlineQueryHandler struct {
+	Bot    *BotAPI
+	Source InlineQuerySource
+
+	// PageSize is the number of results per answer,
+	// MaxInlineQueryResults if zero or larger.
+	PageSize int
+	// Deadline is the time to answer a query,
+	// DefaultInlineQueryDeadline if zero.
+	Deadline time.Duration
+	// CacheTTL is how long results are cached per user and query, or
+	// zero not to cache them.
+	CacheTTL time.Duration
+
+	// CacheTime, IsPersonal, SwitchPMText and SwitchPMParameter are used
+	// for every answer, as in InlineConfig.
+	CacheTime         int
+	IsPersonal        bool
+	SwitchPMText      string
+	SwitchPMParameter string
+
+	// Tracker remembers the answered results if set, to tie
+	// ChosenInlineResult updates back to them.
+	Tracker *InlineResultTracker
+
+	// OnError is called if a query could not be answered. If it is nil,
+	// the error is logged by the Bot.
+	OnError func(query *InlineQuery, err error)
+
+	mu      sync.Mutex
+	cache   map[string]inlineCacheEntry
+	pruneAt int
+}
+
+// NewInlineQueryHandler creates a new InlineQueryHandler answering queries
+// with the results of source.
+func NewInlineQueryHandler(bot *BotAPI, source InlineQuerySource) *InlineQueryHandler {
+	return &InlineQueryHandler{
+		Bot:    bot,
+		Source: source,
+	}
+}
+
+// HandleUpdate answers update if it is an InlineQuery and returns if it
+// was.
+func (h *InlineQueryHandler) HandleUpdate(update Update) bool {
+	if update.InlineQuery == nil {
+		return false
+	}
+
+	if err := h.HandleInlineQuery(update.InlineQuery); err != nil {
+		if h.OnError != nil {
+			h.OnError(update.InlineQuery, err)
+		} else {
+			h.Bot.logEvent(LogLevelError, "answering inline query failed",
+				"query", update.InlineQuery.ID, "error", err)
+		}
+	}
+
+	return true
+}
+
+// HandleInlineQuery answers query with the page of results at its offset.
+func (h *InlineQueryHandler) HandleInlineQuery(query *InlineQuery) error {
+	deadline := h.Deadline
+	if deadline <= 0 {
+		deadline = DefaultInlineQueryDeadline
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	results, err := h.results(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	pageSize := h.PageSize
+	if pageSize <= 0 || pageSize > MaxInlineQueryResults {
+		pageSize = MaxInlineQueryResults
+	}
+
+	offset, _ := strconv.Atoi(query.Offset)
+	if offset < 0 || offset > len(results) {
+		offset = len(results)
+	}
+
+	end := offset + pageSize
+	nextOffset := strconv.Itoa(end)
+	if end >= len(results) {
+		end = len(results)
+		nextOffset = ""
+	}
+
+	config := InlineConfig{
+		InlineQueryID:     query.ID,
+		Results:           results[offset:end],
+		CacheTime:         h.CacheTime,
+		IsPersonal:        h.IsPersonal,
+		NextOffset:        nextOffset,
+		SwitchPMText:      h.SwitchPMText,
+		SwitchPMParameter: h.SwitchPMParameter,
+	}
+
+	if _, err := h.Bot.RequestContext(ctx, config); err != nil {
+		return err
+	}
+
+	if h.Tracker != nil {
+		h.Tracker.Served(query, config.Results, offset)
+	}
+
+	return nil
+}
+
+// results returns the cached results for query, or gets and validates
+// them from the Source.
+func (h *InlineQueryHandler) results(ctx context.Context, query *InlineQuery) ([]InlineQueryResult, error) {
+	key := query.Query
+	if query.From != nil {
+		key = strconv.FormatInt(query.From.ID, 10) + ":" + key
+	}
+
+	if h.CacheTTL > 0 {
+		h.mu.Lock()
+		entry, ok := h.cache[key]
+		h.mu.Unlock()
+
+		if ok && time.Now().Before(entry.expires) {
+			return entry.results, nil
+		}
+	}
+
+	// The Source runs on its own, so the deadline is kept even if it
+	// ignores ctx.
+	done := make(chan inlineSourceResult, 1)
+	go func() {
+		results, err := h.Source(ctx, query)
+		done <- inlineSourceResult{results: results, err: err}
+	}()
+
+	var results []InlineQueryResult
+	select {
+	case result := <-done:
+		if result.err != nil {
+			return nil, result.err
+		}
+		results = result.results
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateInlineResultIDs(results); err != nil {
+		return nil, err
+	}
+
+	if h.CacheTTL > 0 {
+		h.mu.Lock()
+		if h.cache == nil {
+			h.cache = make(map[string]inlineCacheEntry)
+		}
+
+		now := time.Now()
+		if len(h.cache) >= minInlineCachePrune && len(h.cache) >= h.pruneAt {
+			h.pruneCache(now)
+		}
+		h.cache[key] = inlineCacheEntry{results: results, expires: now.Add(h.CacheTTL)}
+		h.mu.Unlock()
+	}
+
+	return results, nil
+}
+
+// pruneCache removes expired results from the cache once it has twice as
+// many entries as after they were removed last, so removing them takes
+// constant time per query on average. h.mu must be held.
+func (h *InlineQueryHandler) pruneCache(now time.Time) {
+	for k, entry := range h.cache {
+		if now.After(entry.expires) {
+			delete(h.cache, k)
+		}
+	}
+
+	h.pruneAt = 2 * len(h.cache)
+}
+
+// validateInlineResultIDs checks that no result is nil and that every
+// result has a unique ID of at most MaxInlineResultIDLength bytes.
+func validateInlineResultIDs(results []InlineQueryResult) error {
+	ids := make(map[string]bool, len(results))
+
+	for _, result := range results {
+		if v := reflect.ValueOf(result); !v.IsValid() || v.Kind() == reflect.Ptr && v.IsNil() {
+			return errors.New(ErrInlineResultNil)
+		}
+
+		id := result.ResultID()
+		if id == "" || len(id) > MaxInlineResultIDLength {
+			return errors.New(ErrInlineResultID)
+		}
+
+		if ids[id] {
+			return errors.New(ErrInlineResultIDDuplicate)
+		}
+		ids[id] = true
+	}
+
+	return nil
+}
+
+// marshalInlineQueryResults encodes results as JSON, setting the type of
+// results without one. Results passed as pointers are copied rather than
+// changed.
+func marshalInlineQueryResults(results []InlineQueryResult) (string, error) {
+	typed := make([]interface{}, len(results))
+
+	for i, result := range results {
+		typed[i] = result
+
+		v := reflect.Indirect(reflect.ValueOf(result))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+
+		if field := v.FieldByName("Type"); field.Kind() == reflect.String && field.String() == "" {
+			copied := reflect.New(v.Type()).Elem()
+			copied.Set(v)
+			copied.FieldByName("Type").SetString(result.ResultType())
+			typed[i] = copied.Interface()
+		}
+	}
+
+	data, err := json.Marshal(typed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
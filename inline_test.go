@@ -0,0 +1,233 @@
+package tgbotapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestInlineQueryHandler(t *testing.T) {
+	type answer struct {
+		ids        []string
+		nextOffset string
+	}
+	var answers []answer
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		var results []tgbotapi.InlineQueryResultArticle
+		json.Unmarshal([]byte(req.FormValue("results")), &results)
+
+		a := answer{nextOffset: req.FormValue("next_offset")}
+		for _, result := range results {
+			a.ids = append(a.ids, result.ID)
+		}
+		answers = append(answers, a)
+
+		return true
+	})
+
+	calls := 0
+	source := func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+		calls++
+
+		var results []tgbotapi.InlineQueryResult
+		for i := 0; i < 120; i++ {
+			id := strconv.Itoa(i)
+			results = append(results, tgbotapi.NewInlineQueryResultArticle(id, query.Query+" "+id, id))
+		}
+
+		return results, nil
+	}
+
+	// The cache must work without NewInlineQueryHandler as well.
+	handler := &tgbotapi.InlineQueryHandler{Bot: bot, Source: source, CacheTTL: time.Minute}
+
+	user := &tgbotapi.User{ID: 1}
+	for _, offset := range []string{"", "50", "100"} {
+		handler.HandleUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{
+			ID:     "query",
+			From:   user,
+			Query:  "search",
+			Offset: offset,
+		}})
+	}
+
+	if calls != 1 {
+		t.Errorf("results were not cached, source called %d times", calls)
+	}
+
+	if len(answers) != 3 {
+		t.Fatal(answers)
+	}
+	if len(answers[0].ids) != 50 || answers[0].ids[0] != "0" || answers[0].nextOffset != "50" {
+		t.Error(answers[0])
+	}
+	if len(answers[2].ids) != 20 || answers[2].ids[0] != "100" || answers[2].nextOffset != "" {
+		t.Error(answers[2])
+	}
+}
+
+func TestInlineQueryHandlerResultIDs(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Error("invalid results were sent")
+		return true
+	})
+
+	for _, test := range []struct {
+		ids []string
+		err string
+	}{
+		{[]string{"a", "a"}, tgbotapi.ErrInlineResultIDDuplicate},
+		{[]string{strings.Repeat("a", tgbotapi.MaxInlineResultIDLength+1)}, tgbotapi.ErrInlineResultID},
+		{[]string{""}, tgbotapi.ErrInlineResultID},
+	} {
+		ids := test.ids
+		handler := tgbotapi.NewInlineQueryHandler(bot, func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+			var results []tgbotapi.InlineQueryResult
+			for _, id := range ids {
+				results = append(results, tgbotapi.NewInlineQueryResultArticle(id, "title", "text"))
+			}
+
+			return results, nil
+		})
+
+		err := handler.HandleInlineQuery(&tgbotapi.InlineQuery{ID: "query"})
+		if err == nil || err.Error() != test.err {
+			t.Errorf("expected %q, got %v", test.err, err)
+		}
+	}
+}
+
+func TestInlineQueryHandlerNilResult(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Error("invalid results were sent")
+		return true
+	})
+
+	for _, result := range []tgbotapi.InlineQueryResult{nil, (*tgbotapi.InlineQueryResultArticle)(nil)} {
+		result := result
+		handler := tgbotapi.NewInlineQueryHandler(bot, func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+			return []tgbotapi.InlineQueryResult{tgbotapi.NewInlineQueryResultArticle("a", "title", "text"), result}, nil
+		})
+
+		err := handler.HandleInlineQuery(&tgbotapi.InlineQuery{ID: "query"})
+		if err == nil || err.Error() != tgbotapi.ErrInlineResultNil {
+			t.Errorf("expected %q, got %v", tgbotapi.ErrInlineResultNil, err)
+		}
+	}
+}
+
+func TestInlineQueryHandlerDeadline(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Error("query was answered after the deadline")
+		return true
+	})
+
+	handler := tgbotapi.NewInlineQueryHandler(bot, func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+		<-ctx.Done()
+		return nil, nil
+	})
+	handler.Deadline = 10 * time.Millisecond
+
+	if err := handler.HandleInlineQuery(&tgbotapi.InlineQuery{ID: "query"}); err != context.DeadlineExceeded {
+		t.Error(err)
+	}
+}
+
+func TestInlineQueryHandlerDeadlineIgnored(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Error("query was answered after the deadline")
+		return true
+	})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	handler := tgbotapi.NewInlineQueryHandler(bot, func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+		<-release
+		return nil, nil
+	})
+	handler.Deadline = 10 * time.Millisecond
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- handler.HandleInlineQuery(&tgbotapi.InlineQuery{ID: "query"})
+	}()
+
+	select {
+	case err := <-errs:
+		if err != context.DeadlineExceeded {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Error("the handler waited for the source past the deadline")
+	}
+}
+
+func TestInlineQueryHandlerLogsErrors(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return true
+	})
+	logger := &recordingLogger{}
+	bot.Logger = logger
+
+	handler := tgbotapi.NewInlineQueryHandler(bot, func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+		return []tgbotapi.InlineQueryResult{tgbotapi.NewInlineQueryResultArticle("", "title", "text")}, nil
+	})
+
+	handler.HandleUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{ID: "query"}})
+
+	if out := logger.out.String(); !strings.Contains(out, "level=ERROR") || !strings.Contains(out, tgbotapi.ErrInlineResultID) {
+		t.Errorf("expected the error to be logged, got %q", out)
+	}
+}
+
+func TestInlineConfigResultTypes(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		var results []map[string]interface{}
+		json.Unmarshal([]byte(req.FormValue("results")), &results)
+
+		if len(results) != 2 || results[0]["type"] != "contact" || results[1]["type"] != "article" {
+			t.Error(results)
+		}
+
+		content := results[1]["input_message_content"].(map[string]interface{})
+		if content["currency"] != "EUR" {
+			t.Error(content)
+		}
+
+		return true
+	})
+
+	invoice := tgbotapi.InlineQueryResultArticle{
+		ID:    "invoice",
+		Title: "Buy",
+		InputMessageContent: tgbotapi.NewInputInvoiceMessageContent("Item", "An item", "payload", "token", "EUR",
+			[]tgbotapi.LabeledPrice{{Label: "Item", Amount: 100}}),
+	}
+
+	_, err := bot.AnswerInlineQuery(tgbotapi.InlineConfig{
+		InlineQueryID: "query",
+		Results: []tgbotapi.InlineQueryResult{
+			tgbotapi.NewInlineQueryResultContact("contact", "+1234", "name"),
+			&invoice,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if invoice.Type != "" {
+		t.Error("result passed as a pointer was changed")
+	}
+}
@@ -0,0 +1,292 @@
+package tgbotapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultInlineResultTTL is how long an InlineResultTracker remembers
+// served results if it has no TTL.
+const DefaultInlineResultTTL = 10 * time.Minute
+
+// TrackedInlineResult is a result served for an inline query, as
+// remembered by an InlineResultTracker.
+type TrackedInlineResult struct {
+	Query  *InlineQuery
+	Result InlineQueryResult
+	// Position is the index of the result among all results of the query,
+	// counting from zero.
+	Position int
+	Served   time.Time
+}
+
+// InlineResultStats counts how often results were served and chosen.
+type InlineResultStats struct {
+	Served int
+	Chosen int
+}
+
+// Rate returns the share of served results which were chosen.
+func (s InlineResultStats) Rate() float64 {
+	if s.Served == 0 {
+		return 0
+	}
+
+	return float64(s.Chosen) / float64(s.Served)
+}
+
+// DefaultInlineResultStatsLimit is the number of results an
+// InlineResultTracker keeps statistics for if it has no StatsLimit.
+const DefaultInlineResultStatsLimit = 10000
+
+// minInlineResultPrune is the number of served results from which an
+// InlineResultTracker starts pruning expired ones.
+const minInlineResultPrune = 64
+
+// InlineResultTracker remembers the results served for inline queries, so
+// a ChosenInlineResult can be tied back to its query and result, and
+// counts how often each result is chosen.
+//
+// A result served again to the same user for the same query while it is
+// remembered, such as a page answered from the cache of an
+// InlineQueryHandler, is only counted once.
+//
+// Telegram only sends ChosenInlineResult updates if inline feedback is
+// enabled for the bot with @BotFather.
+type InlineResultTracker struct {
+	// TTL is how long served results are remembered,
+	// DefaultInlineResultTTL if zero.
+	TTL time.Duration
+	// StatsLimit is the number of results and positions statistics are
+	// kept for, DefaultInlineResultStatsLimit if zero. Statistics of the
+	// results served least recently are dropped beyond it, and positions
+	// from it on are not counted.
+	StatsLimit int
+
+	// OnChosen is called for every chosen result which was served. It may
+	// be nil.
+	OnChosen func(chosen *ChosenInlineResult, result TrackedInlineResult)
+
+	mu        sync.Mutex
+	served    map[inlineResultKey]TrackedInlineResult
+	pruneAt   int
+	results   map[string]*inlineResultStats
+	positions map[int]InlineResultStats
+}
+
+// inlineResultKey identifies a result served to a user for a query.
+type inlineResultKey struct {
+	user   int64
+	query  string
+	result string
+}
+
+// newInlineResultKey returns the key of result served to user for query.
+func newInlineResultKey(user *User, query, result string) inlineResultKey {
+	key := inlineResultKey{query: query, result: result}
+	if user != nil {
+		key.user = user.ID
+	}
+
+	return key
+}
+
+// inlineResultStats are the statistics of a result and when it was served
+// last.
+type inlineResultStats struct {
+	InlineResultStats
+	served time.Time
+}
+
+// NewInlineResultTracker creates a new, empty InlineResultTracker.
+func NewInlineResultTracker() *InlineResultTracker {
+	return &InlineResultTracker{}
+}
+
+// Served remembers results served for query, starting at offset among all
+// of its results.
+func (t *InlineResultTracker) Served(query *InlineQuery, results []InlineQueryResult, offset int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.init()
+
+	now := time.Now()
+	t.pruneServed(now)
+
+	for i, result := range results {
+		position := offset + i
+		key := newInlineResultKey(query.From, query.Query, result.ResultID())
+
+		served, ok := t.served[key]
+		t.served[key] = TrackedInlineResult{
+			Query:    query,
+			Result:   result,
+			Position: position,
+			Served:   now,
+		}
+
+		if ok && !t.expired(served, now) {
+			continue
+		}
+
+		stats := t.results[result.ResultID()]
+		if stats == nil {
+			stats = &inlineResultStats{}
+			t.results[result.ResultID()] = stats
+		}
+		stats.Served++
+		stats.served = now
+
+		if position < t.statsLimit() {
+			stats := t.positions[position]
+			stats.Served++
+			t.positions[position] = stats
+		}
+	}
+
+	t.pruneStats()
+}
+
+// HandleUpdate tracks update if it is a ChosenInlineResult and returns if
+// it was.
+func (t *InlineResultTracker) HandleUpdate(update Update) bool {
+	if update.ChosenInlineResult == nil {
+		return false
+	}
+
+	t.Chosen(update.ChosenInlineResult)
+
+	return true
+}
+
+// Chosen counts chosen and returns the result it refers to, if it was
+// served recently.
+//
+// Results stay remembered until TTL passed, since users may choose the
+// same result again from an answer cached by Telegram.
+func (t *InlineResultTracker) Chosen(chosen *ChosenInlineResult) (TrackedInlineResult, bool) {
+	t.mu.Lock()
+
+	t.init()
+
+	key := newInlineResultKey(chosen.From, chosen.Query, chosen.ResultID)
+	result, ok := t.served[key]
+	if ok && t.expired(result, time.Now()) {
+		result, ok = TrackedInlineResult{}, false
+	}
+
+	if ok {
+		if stats := t.results[chosen.ResultID]; stats != nil {
+			stats.Chosen++
+		}
+
+		if stats, counted := t.positions[result.Position]; counted {
+			stats.Chosen++
+			t.positions[result.Position] = stats
+		}
+	}
+
+	t.mu.Unlock()
+
+	if ok && t.OnChosen != nil {
+		t.OnChosen(chosen, result)
+	}
+
+	return result, ok
+}
+
+// Stats returns the statistics of every result by its ID.
+func (t *InlineResultTracker) Stats() map[string]InlineResultStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make(map[string]InlineResultStats, len(t.results))
+	for id, s := range t.results {
+		stats[id] = s.InlineResultStats
+	}
+
+	return stats
+}
+
+// PositionStats returns the statistics of results by their position.
+func (t *InlineResultTracker) PositionStats() map[int]InlineResultStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make(map[int]InlineResultStats, len(t.positions))
+	for position, s := range t.positions {
+		stats[position] = s
+	}
+
+	return stats
+}
+
+// init creates the maps of the tracker if it was not created by
+// NewInlineResultTracker.
+func (t *InlineResultTracker) init() {
+	if t.served == nil {
+		t.served = make(map[inlineResultKey]TrackedInlineResult)
+		t.results = make(map[string]*inlineResultStats)
+		t.positions = make(map[int]InlineResultStats)
+	}
+}
+
+// expired returns if served is no longer remembered at now.
+func (t *InlineResultTracker) expired(served TrackedInlineResult, now time.Time) bool {
+	ttl := t.TTL
+	if ttl <= 0 {
+		ttl = DefaultInlineResultTTL
+	}
+
+	return now.Sub(served.Served) > ttl
+}
+
+// pruneServed removes expired results once their number doubled since
+// they were pruned last, so pruning takes constant time per result on
+// average.
+func (t *InlineResultTracker) pruneServed(now time.Time) {
+	if len(t.served) < t.pruneAt || len(t.served) < minInlineResultPrune {
+		return
+	}
+
+	for key, served := range t.served {
+		if t.expired(served, now) {
+			delete(t.served, key)
+		}
+	}
+
+	t.pruneAt = 2 * len(t.served)
+}
+
+// pruneStats drops the statistics of the results served least recently
+// once there are more than StatsLimit, keeping three quarters of them.
+func (t *InlineResultTracker) pruneStats() {
+	limit := t.statsLimit()
+	if len(t.results) <= limit {
+		return
+	}
+
+	ids := make([]string, 0, len(t.results))
+	for id := range t.results {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return t.results[ids[i]].served.After(t.results[ids[j]].served)
+	})
+
+	for _, id := range ids[limit*3/4:] {
+		delete(t.results, id)
+	}
+}
+
+// statsLimit returns the number of results statistics are kept for.
+func (t *InlineResultTracker) statsLimit() int {
+	if t.StatsLimit <= 0 {
+		return DefaultInlineResultStatsLimit
+	}
+
+	return t.StatsLimit
+}
@@ -0,0 +1,118 @@
+package tgbotapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestInlineResultTracker(t *testing.T) {
+	var edits []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if method == "editMessageText" {
+			req.ParseForm()
+			edits = append(edits, req.FormValue("inline_message_id")+" "+req.FormValue("text"))
+		}
+
+		return true
+	})
+
+	// The tracker must work without NewInlineResultTracker as well.
+	tracker := &tgbotapi.InlineResultTracker{}
+	tracker.OnChosen = func(chosen *tgbotapi.ChosenInlineResult, result tgbotapi.TrackedInlineResult) {
+		edit := tgbotapi.NewEditMessageTextInline(chosen.InlineMessageID, "Chose "+result.Query.Query)
+		if _, err := bot.Send(edit); err != nil {
+			t.Error(err)
+		}
+	}
+
+	handler := tgbotapi.NewInlineQueryHandler(bot, func(ctx context.Context, query *tgbotapi.InlineQuery) ([]tgbotapi.InlineQueryResult, error) {
+		return []tgbotapi.InlineQueryResult{
+			tgbotapi.NewInlineQueryResultArticle("a", "A", "a"),
+			tgbotapi.NewInlineQueryResultArticle("b", "B", "b"),
+		}, nil
+	})
+	handler.Tracker = tracker
+
+	user := &tgbotapi.User{ID: 1}
+	handler.CacheTTL = time.Minute
+
+	// The second query for "first" is answered from the cache and must
+	// not be counted again.
+	for _, query := range []string{"first", "second", "first"} {
+		handler.HandleUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{ID: "query", From: user, Query: query}})
+	}
+
+	handled := tracker.HandleUpdate(tgbotapi.Update{ChosenInlineResult: &tgbotapi.ChosenInlineResult{
+		ResultID:        "b",
+		From:            user,
+		Query:           "first",
+		InlineMessageID: "inline",
+	}})
+	if !handled {
+		t.Error("chosen inline result was not handled")
+	}
+
+	if _, ok := tracker.Chosen(&tgbotapi.ChosenInlineResult{ResultID: "a", From: &tgbotapi.User{ID: 2}, Query: "first"}); ok {
+		t.Error("result served to another user was found")
+	}
+
+	if _, ok := tracker.Chosen(&tgbotapi.ChosenInlineResult{ResultID: "a", From: user, Query: "third"}); ok {
+		t.Error("result served for another query was found")
+	}
+
+	if len(edits) != 1 || edits[0] != "inline Chose first" {
+		t.Error(edits)
+	}
+
+	stats := tracker.Stats()
+	if stats["a"].Served != 2 || stats["a"].Chosen != 0 || stats["b"].Chosen != 1 || stats["b"].Rate() != 0.5 {
+		t.Error(stats)
+	}
+
+	if positions := tracker.PositionStats(); positions[1].Chosen != 1 || positions[0].Served != 2 {
+		t.Error(positions)
+	}
+}
+
+func TestInlineResultTrackerStatsLimit(t *testing.T) {
+	tracker := &tgbotapi.InlineResultTracker{StatsLimit: 4}
+	query := &tgbotapi.InlineQuery{From: &tgbotapi.User{ID: 1}, Query: "query"}
+
+	for i := 0; i < 5; i++ {
+		id := string(rune('a' + i))
+		tracker.Served(query, []tgbotapi.InlineQueryResult{tgbotapi.NewInlineQueryResultArticle(id, id, id)}, i)
+	}
+
+	stats := tracker.Stats()
+	if len(stats) != 3 || stats["e"].Served != 1 {
+		t.Error(stats)
+	}
+
+	if positions := tracker.PositionStats(); len(positions) != 4 {
+		t.Error(positions)
+	}
+}
+
+func TestInlineResultTrackerChosenTwice(t *testing.T) {
+	tracker := &tgbotapi.InlineResultTracker{}
+	query := &tgbotapi.InlineQuery{From: &tgbotapi.User{ID: 1}, Query: "query"}
+	tracker.Served(query, []tgbotapi.InlineQueryResult{tgbotapi.NewInlineQueryResultArticle("a", "A", "a")}, 0)
+
+	// Telegram may show the cached answer again, so the result can be
+	// chosen more than once.
+	for i := 0; i < 2; i++ {
+		result, ok := tracker.Chosen(&tgbotapi.ChosenInlineResult{ResultID: "a", From: query.From, Query: "query"})
+		if !ok || result.Query != query {
+			t.Fatal(result, ok)
+		}
+	}
+
+	if stats := tracker.Stats(); stats["a"].Chosen != 2 {
+		t.Error(stats)
+	}
+}
@@ -0,0 +1,66 @@
+package tgbotapi
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Instrumentation is notified about every request made to the Bot API,
+// including file uploads, for example to collect metrics or traces.
+type Instrumentation interface {
+	// StartRequest is called before a request to method is sent. The
+	// returned context is used for the request and passed to
+	// FinishRequest, so it may carry values such as a span.
+	StartRequest(ctx context.Context, method string) context.Context
+	// FinishRequest is called once the request is done.
+	FinishRequest(ctx context.Context, info RequestInfo)
+}
+
+// RequestInfo describes a finished request to the Bot API.
+type RequestInfo struct {
+	Method        string
+	Upload        bool // if the request was a multipart upload
+	Start         time.Time
+	Duration      time.Duration
+	StatusCode    int // HTTP status code, zero if there was no response
+	ErrorCode     int // error code returned by Telegram
+	Ok            bool
+	BytesSent     int64
+	BytesReceived int64
+	Retries       int // see WithRetryCount
+	Err           error
+}
+
+type retryCountKey struct{}
+
+// WithRetryCount returns a context for requests which are retried for the
+// nth time, so the number is reported to the Instrumentation.
+func WithRetryCount(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, retryCountKey{}, n)
+}
+
+// retryCount returns the number of retries set by WithRetryCount.
+func retryCount(ctx context.Context) int {
+	n, _ := ctx.Value(retryCountKey{}).(int)
+	return n
+}
+
+// countingReader counts the bytes read from r. The request body may still
+// be read by the transport while the response is handled, so the count is
+// updated atomically.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+func (c *countingReader) count() int64 {
+	return atomic.LoadInt64(&c.n)
+}
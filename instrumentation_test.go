@@ -0,0 +1,137 @@
+package tgbotapi_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+type recordingInstrumentation struct {
+	requests []tgbotapi.RequestInfo
+}
+
+func (r *recordingInstrumentation) StartRequest(ctx context.Context, method string) context.Context {
+	return ctx
+}
+
+func (r *recordingInstrumentation) FinishRequest(ctx context.Context, info tgbotapi.RequestInfo) {
+	r.requests = append(r.requests, info)
+}
+
+func TestInstrumentation(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		ioutil.ReadAll(req.Body)
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	recorder := &recordingInstrumentation{}
+	bot.Instrumentation = recorder
+
+	ctx := tgbotapi.WithRetryCount(context.Background(), 2)
+	if _, err := bot.SendContext(ctx, tgbotapi.NewMessage(ChatID, "text")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(tgbotapi.NewDocumentUpload(ChatID, "tests/image.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(recorder.requests))
+	}
+
+	message := recorder.requests[0]
+	if message.Method != "sendMessage" || message.Upload || !message.Ok ||
+		message.StatusCode != http.StatusOK || message.Retries != 2 ||
+		message.BytesSent == 0 || message.BytesReceived == 0 {
+		t.Errorf("%+v", message)
+	}
+
+	upload := recorder.requests[1]
+	if upload.Method != "sendDocument" || !upload.Upload || upload.BytesSent < 1000 {
+		t.Errorf("%+v", upload)
+	}
+}
+
+func TestPrometheusInstrumentation(t *testing.T) {
+	metrics := tgbotapi.NewPrometheusInstrumentation()
+
+	ctx := context.Background()
+	metrics.FinishRequest(ctx, tgbotapi.RequestInfo{Method: "sendMessage", Ok: true, BytesSent: 10, BytesReceived: 20})
+	metrics.FinishRequest(ctx, tgbotapi.RequestInfo{Method: "sendMessage", ErrorCode: 400, Retries: 1})
+	metrics.FinishRequest(ctx, tgbotapi.RequestInfo{Method: "getMe", Ok: true})
+
+	var b bytes.Buffer
+	if _, err := metrics.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE tgbotapi_requests_total counter",
+		`tgbotapi_requests_total{method="sendMessage",result="ok"} 1`,
+		`tgbotapi_requests_total{method="sendMessage",result="400"} 1`,
+		`tgbotapi_requests_total{method="getMe",result="ok"} 1`,
+		`tgbotapi_request_duration_seconds_bucket{method="sendMessage",le="+Inf"} 2`,
+		`tgbotapi_request_duration_seconds_count{method="sendMessage"} 2`,
+		`tgbotapi_request_bytes_total{method="sendMessage"} 10`,
+		`tgbotapi_response_bytes_total{method="sendMessage"} 20`,
+		`tgbotapi_retried_requests_total{method="sendMessage"} 1`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, b.String())
+		}
+	}
+}
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+func TestSpanInstrumentation(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return true
+	})
+
+	var spans []*testSpan
+	bot.Instrumentation = tgbotapi.NewSpanInstrumentation(func(ctx context.Context, name string) (context.Context, tgbotapi.Span) {
+		span := &testSpan{name: name, attributes: make(map[string]interface{})}
+		spans = append(spans, span)
+		return ctx, span
+	})
+
+	if _, err := bot.Request(tgbotapi.DeleteMessageConfig{ChatID: ChatID, MessageID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.name != "telegram deleteMessage" || !span.ended || span.err != nil {
+		t.Errorf("%+v", span)
+	}
+	if span.attributes[tgbotapi.SpanAttributeMethod] != "deleteMessage" ||
+		span.attributes[tgbotapi.SpanAttributeStatusCode] != http.StatusOK {
+		t.Error(span.attributes)
+	}
+}
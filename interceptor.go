@@ -0,0 +1,70 @@
+package tgbotapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// InterceptedRequest is a request to the Bot API as seen by an
+// Interceptor. Interceptors may change it before passing it on.
+type InterceptedRequest struct {
+	Context context.Context
+	Method  string
+
+	// Values are the parameters of a regular request.
+	Values url.Values
+	// Params and Files are the parameters and files of a multipart upload.
+	Params map[string]string
+	Files  []RequestFile
+
+	// Header contains additional HTTP headers to send.
+	Header http.Header
+}
+
+// Upload returns if the request is a multipart upload.
+func (req *InterceptedRequest) Upload() bool {
+	return req.Values == nil
+}
+
+// Param returns the parameter key of the request, regardless of whether
+// it is a regular request or an upload.
+func (req *InterceptedRequest) Param(key string) string {
+	if req.Upload() {
+		return req.Params[key]
+	}
+
+	return req.Values.Get(key)
+}
+
+// RequestHandler sends a request to the Bot API.
+type RequestHandler func(req *InterceptedRequest) (APIResponse, error)
+
+// Interceptor is called around a request to the Bot API. It can inspect
+// or change req, then call next to continue with the next interceptor and
+// finally send the request, and inspect the APIResponse afterwards.
+//
+// An Interceptor may also return without calling next, in which case the
+// request is not sent and its result is returned instead.
+type Interceptor func(req *InterceptedRequest, next RequestHandler) (APIResponse, error)
+
+// intercept passes req through the interceptors of the bot before sending
+// it with send. A response which is not Ok is returned as an Error, even
+// if an interceptor returned it without one.
+func (bot *BotAPI) intercept(req *InterceptedRequest, send RequestHandler) (APIResponse, error) {
+	handler := send
+
+	for i := len(bot.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := bot.Interceptors[i], handler
+		handler = func(req *InterceptedRequest) (APIResponse, error) {
+			return interceptor(req, next)
+		}
+	}
+
+	resp, err := handler(req)
+	if err == nil && !resp.Ok {
+		err = responseError(resp)
+	}
+
+	return resp, err
+}
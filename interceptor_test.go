@@ -0,0 +1,101 @@
+package tgbotapi_test
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestInterceptors(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if req.Header.Get("X-Proxy-Auth") != "secret" {
+			t.Error("missing header added by interceptor")
+		}
+
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	var calls []string
+	bot.Interceptors = []tgbotapi.Interceptor{
+		func(req *tgbotapi.InterceptedRequest, next tgbotapi.RequestHandler) (tgbotapi.APIResponse, error) {
+			calls = append(calls, "first "+req.Method)
+			req.Header.Set("X-Proxy-Auth", "secret")
+
+			resp, err := next(req)
+			calls = append(calls, "first done "+strconv.FormatBool(resp.Ok))
+
+			return resp, err
+		},
+		func(req *tgbotapi.InterceptedRequest, next tgbotapi.RequestHandler) (tgbotapi.APIResponse, error) {
+			calls = append(calls, "second "+req.Param("chat_id"))
+			return next(req)
+		},
+	}
+
+	if _, err := bot.Send(tgbotapi.NewMessage(ChatID, "text")); err != nil {
+		t.Fatal(err)
+	}
+
+	chatID := strconv.Itoa(ChatID)
+	expected := []string{"first sendMessage", "second " + chatID, "first done true"}
+	if len(calls) != len(expected) {
+		t.Fatal(calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], calls[i])
+		}
+	}
+
+	calls = nil
+	if _, err := bot.Send(tgbotapi.NewDocumentUpload(ChatID, "tests/image.jpg")); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 3 || calls[1] != "second "+chatID {
+		t.Error(calls)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Error("request must not be sent")
+		return true
+	})
+
+	blocked := errors.New("chat is blocked")
+	bot.Interceptors = []tgbotapi.Interceptor{
+		func(req *tgbotapi.InterceptedRequest, next tgbotapi.RequestHandler) (tgbotapi.APIResponse, error) {
+			if req.Param("chat_id") == strconv.Itoa(ChatID) {
+				return tgbotapi.APIResponse{}, blocked
+			}
+			return next(req)
+		},
+	}
+
+	if _, err := bot.Send(tgbotapi.NewMessage(ChatID, "text")); err != blocked {
+		t.Errorf("expected the interceptor error, got %v", err)
+	}
+}
+
+func TestInterceptorShortCircuitNotOk(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Error("request must not be sent")
+		return true
+	})
+
+	bot.Interceptors = []tgbotapi.Interceptor{
+		func(req *tgbotapi.InterceptedRequest, next tgbotapi.RequestHandler) (tgbotapi.APIResponse, error) {
+			return tgbotapi.APIResponse{Ok: false, Description: "rate limited"}, nil
+		},
+	}
+
+	if _, err := bot.Send(tgbotapi.NewMessage(ChatID, "text")); err == nil || err.Error() != "rate limited" {
+		t.Errorf("expected the response to be an error, got %v", err)
+	}
+	if _, err := bot.Request(tgbotapi.DeleteMessageConfig{ChatID: ChatID, MessageID: 1}); err == nil {
+		t.Error("expected the response to be an error")
+	}
+}
@@ -0,0 +1,327 @@
+package tgbotapi
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"unicode/utf8"
+)
+
+// keyboardValidator is implemented by reply markups which can be checked
+// before they are sent.
+type keyboardValidator interface {
+	validate() error
+}
+
+// marshalReplyMarkup validates markup if possible and encodes it as JSON.
+func marshalReplyMarkup(markup interface{}) (string, error) {
+	v := reflect.ValueOf(markup)
+	nilPointer := v.Kind() == reflect.Ptr && v.IsNil()
+
+	if validator, ok := markup.(keyboardValidator); ok && !nilPointer {
+		if err := validator.validate(); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(markup)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// validate checks that every button of the keyboard is valid.
+func (markup ReplyKeyboardMarkup) validate() error {
+	for _, row := range markup.Keyboard {
+		for _, button := range row {
+			if err := button.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the button has text and at most one optional
+// field.
+func (button KeyboardButton) validate() error {
+	if button.Text == "" {
+		return errors.New(ErrKeyboardButtonText)
+	}
+
+	if countSet(
+		button.RequestUser != nil,
+		button.RequestChat != nil,
+		button.RequestContact,
+		button.RequestLocation,
+		button.RequestPoll != nil,
+		button.WebApp != nil,
+	) > 1 {
+		return errors.New(ErrKeyboardButtonFields)
+	}
+
+	return nil
+}
+
+// validate checks that every button of the keyboard is valid and that
+// callback game and pay buttons come first.
+func (markup InlineKeyboardMarkup) validate() error {
+	for i, row := range markup.InlineKeyboard {
+		for j, button := range row {
+			if err := button.validate(); err != nil {
+				return err
+			}
+
+			if (button.CallbackGame != nil || button.Pay) && (i != 0 || j != 0) {
+				return errors.New(ErrInlineKeyboardButtonPosition)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the button has text and exactly one optional
+// field.
+func (button InlineKeyboardButton) validate() error {
+	if button.Text == "" {
+		return errors.New(ErrKeyboardButtonText)
+	}
+
+	if countSet(
+		button.URL != nil,
+		button.CallbackData != nil,
+		button.WebApp != nil,
+		button.LoginURL != nil,
+		button.SwitchInlineQuery != nil,
+		button.SwitchInlineQueryCurrentChat != nil,
+		button.SwitchInlineQueryChosenChat != nil,
+		button.CallbackGame != nil,
+		button.Pay,
+	) != 1 {
+		return errors.New(ErrInlineKeyboardButtonFields)
+	}
+
+	return nil
+}
+
+// countSet returns the number of fields which are set.
+func countSet(fields ...bool) int {
+	n := 0
+	for _, set := range fields {
+		if set {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Telegram's limits on the size of keyboards.
+const (
+	MaxInlineKeyboardRowLength = 8
+	MaxInlineKeyboardButtons   = 100
+	MaxReplyKeyboardRowLength  = 12
+	MaxReplyKeyboardButtons    = 300
+)
+
+// KeyboardBuilder lays out buttons into a ReplyKeyboardMarkup or an
+// InlineKeyboardMarkup.
+//
+// Buttons added together are arranged into rows by the current layout,
+// which is one button per row unless changed by Columns or MaxWidth. Row
+// and InlineRow add a single row as is.
+type KeyboardBuilder struct {
+	columns  int
+	maxWidth int
+
+	// rows contain KeyboardButtons or InlineKeyboardButtons.
+	rows [][]interface{}
+}
+
+// NewKeyboardBuilder creates a new, empty KeyboardBuilder.
+func NewKeyboardBuilder() *KeyboardBuilder {
+	return &KeyboardBuilder{}
+}
+
+// Columns arranges buttons added afterwards into rows of n buttons.
+func (b *KeyboardBuilder) Columns(n int) *KeyboardBuilder {
+	b.columns = n
+	return b
+}
+
+// MaxWidth arranges buttons added afterwards into rows whose texts are at
+// most n characters long in total. A button longer than n gets its own
+// row. If Columns is set as well, rows are limited by both.
+func (b *KeyboardBuilder) MaxWidth(n int) *KeyboardBuilder {
+	b.maxWidth = n
+	return b
+}
+
+// Buttons adds buttons to a reply keyboard, arranged by the current
+// layout.
+func (b *KeyboardBuilder) Buttons(buttons ...KeyboardButton) *KeyboardBuilder {
+	items := make([]interface{}, len(buttons))
+	texts := make([]string, len(buttons))
+	for i, button := range buttons {
+		items[i], texts[i] = button, button.Text
+	}
+
+	b.layout(items, texts)
+	return b
+}
+
+// InlineButtons adds buttons to an inline keyboard, arranged by the
+// current layout.
+func (b *KeyboardBuilder) InlineButtons(buttons ...InlineKeyboardButton) *KeyboardBuilder {
+	items := make([]interface{}, len(buttons))
+	texts := make([]string, len(buttons))
+	for i, button := range buttons {
+		items[i], texts[i] = button, button.Text
+	}
+
+	b.layout(items, texts)
+	return b
+}
+
+// Row adds a row of buttons to a reply keyboard.
+func (b *KeyboardBuilder) Row(buttons ...KeyboardButton) *KeyboardBuilder {
+	row := make([]interface{}, len(buttons))
+	for i, button := range buttons {
+		row[i] = button
+	}
+
+	b.rows = append(b.rows, row)
+	return b
+}
+
+// InlineRow adds a row of buttons to an inline keyboard.
+func (b *KeyboardBuilder) InlineRow(buttons ...InlineKeyboardButton) *KeyboardBuilder {
+	row := make([]interface{}, len(buttons))
+	for i, button := range buttons {
+		row[i] = button
+	}
+
+	b.rows = append(b.rows, row)
+	return b
+}
+
+// If calls fn with the builder to add rows only if condition is true.
+func (b *KeyboardBuilder) If(condition bool, fn func(b *KeyboardBuilder)) *KeyboardBuilder {
+	if condition {
+		fn(b)
+	}
+
+	return b
+}
+
+// ReplyMarkup builds a reply keyboard from the buttons added with Buttons
+// and Row.
+func (b *KeyboardBuilder) ReplyMarkup() (ReplyKeyboardMarkup, error) {
+	if err := b.checkLimits(MaxReplyKeyboardRowLength, MaxReplyKeyboardButtons); err != nil {
+		return ReplyKeyboardMarkup{}, err
+	}
+
+	keyboard := make([][]KeyboardButton, 0, len(b.rows))
+	for _, items := range b.rows {
+		row := make([]KeyboardButton, 0, len(items))
+		for _, item := range items {
+			button, ok := item.(KeyboardButton)
+			if !ok {
+				return ReplyKeyboardMarkup{}, errors.New(ErrKeyboardButtonKind)
+			}
+
+			row = append(row, button)
+		}
+
+		keyboard = append(keyboard, row)
+	}
+
+	markup := NewReplyKeyboard(keyboard...)
+	if err := markup.validate(); err != nil {
+		return ReplyKeyboardMarkup{}, err
+	}
+
+	return markup, nil
+}
+
+// InlineMarkup builds an inline keyboard from the buttons added with
+// InlineButtons and InlineRow.
+func (b *KeyboardBuilder) InlineMarkup() (InlineKeyboardMarkup, error) {
+	if err := b.checkLimits(MaxInlineKeyboardRowLength, MaxInlineKeyboardButtons); err != nil {
+		return InlineKeyboardMarkup{}, err
+	}
+
+	keyboard := make([][]InlineKeyboardButton, 0, len(b.rows))
+	for _, items := range b.rows {
+		row := make([]InlineKeyboardButton, 0, len(items))
+		for _, item := range items {
+			button, ok := item.(InlineKeyboardButton)
+			if !ok {
+				return InlineKeyboardMarkup{}, errors.New(ErrKeyboardButtonKind)
+			}
+
+			row = append(row, button)
+		}
+
+		keyboard = append(keyboard, row)
+	}
+
+	markup := NewInlineKeyboardMarkup(keyboard...)
+	if err := markup.validate(); err != nil {
+		return InlineKeyboardMarkup{}, err
+	}
+
+	return markup, nil
+}
+
+// layout arranges items, whose button texts are texts, into rows.
+func (b *KeyboardBuilder) layout(items []interface{}, texts []string) {
+	columns := b.columns
+	if columns <= 0 && b.maxWidth <= 0 {
+		columns = 1
+	}
+
+	var row []interface{}
+	width := 0
+
+	for i, item := range items {
+		textWidth := utf8.RuneCountInString(texts[i])
+
+		full := columns > 0 && len(row) >= columns
+		wide := b.maxWidth > 0 && width+textWidth > b.maxWidth
+		if len(row) > 0 && (full || wide) {
+			b.rows = append(b.rows, row)
+			row, width = nil, 0
+		}
+
+		row = append(row, item)
+		width += textWidth
+	}
+
+	if len(row) > 0 {
+		b.rows = append(b.rows, row)
+	}
+}
+
+// checkLimits checks that no row is longer than rowLength and that there
+// are at most buttons buttons.
+func (b *KeyboardBuilder) checkLimits(rowLength, buttons int) error {
+	total := 0
+	for _, row := range b.rows {
+		if len(row) > rowLength {
+			return errors.New(ErrKeyboardRowTooLong)
+		}
+
+		total += len(row)
+	}
+
+	if total > buttons {
+		return errors.New(ErrKeyboardTooManyButtons)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package tgbotapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestKeyboardButtonJSON(t *testing.T) {
+	markup := tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(
+		tgbotapi.NewKeyboardButtonPoll("Poll", "quiz"),
+		tgbotapi.NewKeyboardButtonRequestChat("Channel", 1, true),
+		tgbotapi.NewKeyboardButtonWebApp("App", "https://example.com"),
+	))
+	markup.InputFieldPlaceholder = "Choose"
+
+	data, err := json.Marshal(markup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"keyboard":[[` +
+		`{"text":"Poll","request_contact":false,"request_location":false,"request_poll":{"type":"quiz"}},` +
+		`{"text":"Channel","request_chat":{"request_id":1,"chat_is_channel":true},"request_contact":false,"request_location":false},` +
+		`{"text":"App","request_contact":false,"request_location":false,"web_app":{"url":"https://example.com"}}` +
+		`]],"resize_keyboard":true,"one_time_keyboard":false,"input_field_placeholder":"Choose","selective":false}`
+	if string(data) != expected {
+		t.Error(string(data))
+	}
+}
+
+func TestKeyboardValidation(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	twoFields := tgbotapi.NewKeyboardButtonContact("Contact")
+	twoFields.RequestLocation = true
+
+	noAction := tgbotapi.InlineKeyboardButton{Text: "Nothing"}
+
+	game := tgbotapi.InlineKeyboardButton{Text: "Play", CallbackGame: &tgbotapi.CallbackGame{}}
+
+	tests := []struct {
+		markup interface{}
+		err    string
+	}{
+		{tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(twoFields)), tgbotapi.ErrKeyboardButtonFields},
+		{tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(tgbotapi.KeyboardButton{})), tgbotapi.ErrKeyboardButtonText},
+		{tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(noAction)), tgbotapi.ErrInlineKeyboardButtonFields},
+		{tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("First", "data"), game,
+		)), tgbotapi.ErrInlineKeyboardButtonPosition},
+		{tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			game, tgbotapi.NewInlineKeyboardButtonWebApp("App", "https://example.com"),
+		)), ""},
+		{tgbotapi.NewForceReply("Reply here", false), ""},
+	}
+
+	for _, test := range tests {
+		msg := tgbotapi.NewMessage(ChatID, "text")
+		msg.ReplyMarkup = test.markup
+
+		_, err := bot.Send(msg)
+		if test.err == "" && err != nil {
+			t.Error(err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("expected %q, got %v", test.err, err)
+		}
+	}
+}
+
+func TestKeyboardValidationUpload(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		t.Errorf("unexpected %s request", method)
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.InlineKeyboardButton{Text: "Nothing"},
+	))
+
+	photo := tgbotapi.NewPhotoUpload(ChatID, "tests/image.jpg")
+	photo.ReplyMarkup = markup
+
+	document := tgbotapi.NewDocumentUpload(ChatID, "tests/image.jpg")
+	document.ReplyMarkup = markup
+
+	voice := tgbotapi.NewVoiceUpload(ChatID, "tests/voice.ogg")
+	voice.ReplyMarkup = markup
+
+	for _, msg := range []tgbotapi.Chattable{photo, document, voice} {
+		if _, err := bot.Send(msg); err == nil || err.Error() != tgbotapi.ErrInlineKeyboardButtonFields {
+			t.Errorf("expected %q, got %v", tgbotapi.ErrInlineKeyboardButtonFields, err)
+		}
+	}
+}
+
+func TestKeyboardBuilderColumns(t *testing.T) {
+	isAdmin := false
+
+	markup, err := tgbotapi.NewKeyboardBuilder().
+		Columns(2).
+		InlineButtons(
+			tgbotapi.NewInlineKeyboardButtonData("1", "1"),
+			tgbotapi.NewInlineKeyboardButtonData("2", "2"),
+			tgbotapi.NewInlineKeyboardButtonData("3", "3"),
+		).
+		If(isAdmin, func(b *tgbotapi.KeyboardBuilder) {
+			b.InlineRow(tgbotapi.NewInlineKeyboardButtonData("Admin", "admin"))
+		}).
+		InlineRow(tgbotapi.NewInlineKeyboardButtonURL("Help", "https://example.com")).
+		InlineMarkup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyboard := markup.InlineKeyboard
+	if len(keyboard) != 3 || len(keyboard[0]) != 2 || len(keyboard[1]) != 1 || keyboard[2][0].Text != "Help" {
+		t.Error(keyboard)
+	}
+}
+
+func TestKeyboardBuilderMaxWidth(t *testing.T) {
+	markup, err := tgbotapi.NewKeyboardBuilder().
+		MaxWidth(10).
+		Buttons(
+			tgbotapi.NewKeyboardButton("Yes"),
+			tgbotapi.NewKeyboardButton("No"),
+			tgbotapi.NewKeyboardButton("Maybe"),
+			tgbotapi.NewKeyboardButton("Ask me again later"),
+			tgbotapi.NewKeyboardButton("Never"),
+		).
+		ReplyMarkup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []int
+	for _, row := range markup.Keyboard {
+		rows = append(rows, len(row))
+	}
+
+	if len(rows) != 3 || rows[0] != 3 || rows[1] != 1 || rows[2] != 1 {
+		t.Error(rows)
+	}
+}
+
+func TestKeyboardBuilderLimits(t *testing.T) {
+	var buttons []tgbotapi.InlineKeyboardButton
+	for i := 0; i < tgbotapi.MaxInlineKeyboardRowLength+1; i++ {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("x", "x"))
+	}
+
+	_, err := tgbotapi.NewKeyboardBuilder().InlineRow(buttons...).InlineMarkup()
+	if err == nil || err.Error() != tgbotapi.ErrKeyboardRowTooLong {
+		t.Error(err)
+	}
+
+	for len(buttons) <= tgbotapi.MaxInlineKeyboardButtons {
+		buttons = append(buttons, buttons...)
+	}
+
+	_, err = tgbotapi.NewKeyboardBuilder().Columns(4).InlineButtons(buttons...).InlineMarkup()
+	if err == nil || err.Error() != tgbotapi.ErrKeyboardTooManyButtons {
+		t.Error(err)
+	}
+
+	_, err = tgbotapi.NewKeyboardBuilder().Row(tgbotapi.NewKeyboardButton("x")).InlineMarkup()
+	if err == nil || err.Error() != tgbotapi.ErrKeyboardButtonKind {
+		t.Error(err)
+	}
+}
+
+func TestMessageSharedByKeyboardButtons(t *testing.T) {
+	var message tgbotapi.Message
+	data := `{
+		"message_id": 1,
+		"user_shared": {"request_id": 1, "user_id": 4503599627370495},
+		"chat_shared": {"request_id": 2, "chat_id": -1001120141283},
+		"web_app_data": {"data": "payload", "button_text": "App"}
+	}`
+
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.UserShared == nil || message.UserShared.UserID != 4503599627370495 {
+		t.Error(message.UserShared)
+	}
+	if message.ChatShared == nil || message.ChatShared.RequestID != 2 || message.ChatShared.ChatID != SupergroupChatID {
+		t.Error(message.ChatShared)
+	}
+	if message.WebAppData == nil || message.WebAppData.Data != "payload" || message.WebAppData.ButtonText != "App" {
+		t.Error(message.WebAppData)
+	}
+}
@@ -0,0 +1,85 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+)
+
+// StreamLiveLocation sends config as a live location and updates it with
+// every Location received from locations.
+//
+// Once locations is closed or ctx is done, the live location is stopped
+// and the sent message is returned, along with the error stopping it.
+// Updates are also stopped if one of them fails, for example because the
+// live period expired, and the error of the update is returned instead.
+func (bot *BotAPI) StreamLiveLocation(ctx context.Context, config LocationConfig, locations <-chan Location) (Message, error) {
+	if config.LivePeriod == 0 {
+		return Message{}, errors.New(ErrLivePeriod)
+	}
+
+	message, err := bot.SendContext(ctx, config)
+	if err != nil {
+		return Message{}, err
+	}
+
+	edit := BaseEdit{
+		ChatID:          config.ChatID,
+		ChannelUsername: config.ChannelUsername,
+		MessageID:       message.MessageID,
+	}
+
+	for err == nil {
+		select {
+		case location, ok := <-locations:
+			if !ok {
+				return message, bot.stopLiveLocation(edit)
+			}
+
+			_, err = bot.RequestContext(ctx, EditMessageLiveLocationConfig{
+				BaseEdit:  edit,
+				Latitude:  location.Latitude,
+				Longitude: location.Longitude,
+			})
+
+			// Sending the same location again fails.
+			if isNotModified(err) {
+				err = nil
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	// ctx may be done, but the live location must be stopped anyway. The
+	// error ending the updates is returned, so a failure to stop is only
+	// logged.
+	if stopErr := bot.stopLiveLocation(edit); stopErr != nil {
+		bot.logEvent(LogLevelError, "stopping live location failed",
+			"message_id", message.MessageID, "error", stopErr)
+	}
+
+	return message, err
+}
+
+// stopLiveLocation stops updating the live location of edit.
+//
+// A live location whose period expired can not be stopped anymore, which
+// is not treated as an error.
+func (bot *BotAPI) stopLiveLocation(edit BaseEdit) error {
+	_, err := bot.Request(StopMessageLiveLocationConfig{BaseEdit: edit})
+	if isNotModified(err) {
+		return nil
+	}
+
+	return err
+}
+
+// validateLivePeriod checks that a live period in seconds is either unset
+// or within the limits of Telegram.
+func validateLivePeriod(period int) error {
+	if period != 0 && (period < MinLivePeriod || period > MaxLivePeriod) {
+		return errors.New(ErrLivePeriodRange)
+	}
+
+	return nil
+}
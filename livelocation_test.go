@@ -0,0 +1,107 @@
+package tgbotapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestEditMessageMediaUpload(t *testing.T) {
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if method != "editMessageMedia" {
+			t.Error(method)
+		}
+
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+
+		if media := req.FormValue("media"); !strings.Contains(media, `"media":"attach://file-0"`) {
+			t.Error(media)
+		}
+		if req.FormValue("message_id") != "5" {
+			t.Error(req.Form)
+		}
+
+		file, _, err := req.FormFile("file-0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(file)
+		if string(data) != "photo" {
+			t.Error(string(data))
+		}
+
+		return tgbotapi.Message{MessageID: 5}
+	})
+
+	media := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: "photo.jpg", Bytes: []byte("photo")})
+
+	message, err := bot.Send(tgbotapi.NewEditMessageMedia(ChatID, 5, media))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.MessageID != 5 {
+		t.Error(message)
+	}
+}
+
+func TestStreamLiveLocation(t *testing.T) {
+	var calls []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		calls = append(calls, method+" "+req.FormValue("latitude")+" "+req.FormValue("live_period"))
+
+		if method == "sendLocation" {
+			return tgbotapi.Message{MessageID: 7}
+		}
+		if req.FormValue("message_id") != "7" {
+			t.Error(req.Form)
+		}
+
+		return true
+	})
+
+	locations := make(chan tgbotapi.Location, 2)
+	locations <- tgbotapi.Location{Latitude: 1, Longitude: 1}
+	locations <- tgbotapi.Location{Latitude: 2, Longitude: 2}
+	close(locations)
+
+	message, err := bot.StreamLiveLocation(context.Background(), tgbotapi.NewLiveLocation(ChatID, 0, 0, 60), locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.MessageID != 7 {
+		t.Error(message)
+	}
+
+	expected := []string{
+		"sendLocation 0.000000 60",
+		"editMessageLiveLocation 1.000000 ",
+		"editMessageLiveLocation 2.000000 ",
+		"stopMessageLiveLocation  ",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Error(calls)
+	}
+
+	if _, err := bot.StreamLiveLocation(context.Background(), tgbotapi.NewLocation(ChatID, 0, 0), locations); err == nil || err.Error() != tgbotapi.ErrLivePeriod {
+		t.Error(err)
+	}
+
+	for _, period := range []int{59, 86401} {
+		if _, err := bot.Send(tgbotapi.NewLiveLocation(ChatID, 0, 0, period)); err == nil || err.Error() != tgbotapi.ErrLivePeriodRange {
+			t.Errorf("expected %q for %d, got %v", tgbotapi.ErrLivePeriodRange, period, err)
+		}
+	}
+
+	edit := tgbotapi.EditMessageLiveLocationConfig{BaseEdit: tgbotapi.BaseEdit{ChatID: ChatID, MessageID: 7}, LivePeriod: 30}
+	if _, err := bot.Send(edit); err == nil || err.Error() != tgbotapi.ErrLivePeriodRange {
+		t.Error(err)
+	}
+}
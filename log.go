@@ -2,8 +2,13 @@ package tgbotapi
 
 import (
 	"errors"
+	"fmt"
 	stdlog "log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // BotLogger is an interface that represents the required methods to log data.
@@ -25,3 +30,129 @@ func SetLogger(logger BotLogger) error {
 	log = logger
 	return nil
 }
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+// Log levels of a StructuredLogger.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return "LEVEL(" + strconv.Itoa(int(level)) + ")"
+	}
+}
+
+// StructuredLogger is an interface for loggers which log messages with a
+// level and fields given as alternating keys and values.
+//
+// A BotAPI removes its token from all messages and fields before passing
+// them to its logger.
+type StructuredLogger interface {
+	Log(level LogLevel, msg string, keyvals ...interface{})
+}
+
+// botLogger is the StructuredLogger used if a BotAPI has none. It writes
+// to the logger set by SetLogger.
+type botLogger struct{}
+
+func (botLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	var b strings.Builder
+
+	b.WriteString(level.String())
+	b.WriteByte(' ')
+	b.WriteString(msg)
+
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		fmt.Fprintf(&b, " %v=%+v", keyvals[i], value)
+	}
+
+	log.Println(b.String())
+}
+
+// redactedToken replaces the token of a bot in logs.
+const redactedToken = "<token>"
+
+// logEvent logs msg with keyvals to the logger of the bot, after removing
+// the token of the bot from them.
+func (bot *BotAPI) logEvent(level LogLevel, msg string, keyvals ...interface{}) {
+	logger := bot.Logger
+	if logger == nil {
+		logger = botLogger{}
+	}
+
+	redacted := make([]interface{}, len(keyvals))
+	for i, value := range keyvals {
+		redacted[i] = bot.redactValue(value)
+	}
+
+	logger.Log(level, bot.redact(msg), redacted...)
+}
+
+// redact removes the token of the bot from s.
+func (bot *BotAPI) redact(s string) string {
+	if bot.Token == "" {
+		return s
+	}
+
+	s = strings.Replace(s, bot.Token, redactedToken, -1)
+	return strings.Replace(s, url.QueryEscape(bot.Token), redactedToken, -1)
+}
+
+// redactValue removes the token of the bot from a logged value. Values
+// other than numbers and booleans are formatted as strings to make sure
+// the token can not be part of them.
+func (bot *BotAPI) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Duration, time.Time, LogLevel:
+		return v
+	case string:
+		return bot.redact(v)
+	case error:
+		return bot.redact(v.Error())
+	default:
+		return bot.redact(fmt.Sprintf("%+v", v))
+	}
+}
+
+// redactURLError removes the token of the bot from an error returned by
+// its Client, which contains the URL of the request.
+//
+// The underlying error is only replaced if it contains the token, so
+// checks such as Timeout keep working otherwise.
+func (bot *BotAPI) redactURLError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		if redacted := bot.redact(err.Error()); redacted != err.Error() {
+			return errors.New(redacted)
+		}
+		return err
+	}
+
+	urlErr.URL = bot.redact(urlErr.URL)
+	if urlErr.Err != nil && bot.redact(urlErr.Err.Error()) != urlErr.Err.Error() {
+		urlErr.Err = errors.New(bot.redact(urlErr.Err.Error()))
+	}
+
+	return urlErr
+}
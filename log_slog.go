@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+package tgbotapi
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger is a StructuredLogger which writes to a *slog.Logger.
+type SlogLogger struct {
+	Logger *slog.Logger
+}
+
+// NewSlogLogger creates a StructuredLogger writing to logger.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	return &SlogLogger{
+		Logger: logger,
+	}
+}
+
+// Log writes msg with keyvals as attributes at the matching slog level.
+func (l *SlogLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	l.Logger.Log(context.Background(), slogLevel(level), msg, keyvals...)
+}
+
+// slogLevel converts level to a slog.Level.
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,35 @@
+//go:build go1.21
+// +build go1.21
+
+package tgbotapi_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var out bytes.Buffer
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		return tgbotapi.Message{Text: "echo " + TestToken}
+	})
+	bot.Debug = true
+	bot.Logger = tgbotapi.NewSlogLogger(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if _, err := bot.Send(tgbotapi.NewMessage(ChatID, "text")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "level=DEBUG") || !strings.Contains(out.String(), "method=sendMessage") {
+		t.Errorf("expected debug output, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), TestToken) {
+		t.Errorf("log contains the token:\n%s", out.String())
+	}
+}
@@ -0,0 +1,67 @@
+package tgbotapi_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// recordingLogger is a StructuredLogger writing every entry as a line.
+type recordingLogger struct {
+	out bytes.Buffer
+}
+
+func (l *recordingLogger) Log(level tgbotapi.LogLevel, msg string, keyvals ...interface{}) {
+	fmt.Fprintf(&l.out, "level=%s msg=%q", level, msg)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fmt.Fprintf(&l.out, " %v=%v", keyvals[i], keyvals[i+1])
+	}
+	l.out.WriteByte('\n')
+}
+
+func TestLoggerRedactsToken(t *testing.T) {
+	logger := &recordingLogger{}
+
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused to " + req.URL.String())
+		}),
+	}
+
+	bot := &tgbotapi.BotAPI{
+		Token:  TestToken,
+		Client: client,
+		Debug:  true,
+		Logger: logger,
+	}
+
+	_, err := bot.Send(tgbotapi.NewMessage(ChatID, "text"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if strings.Contains(err.Error(), TestToken) {
+		t.Errorf("error contains the token: %v", err)
+	}
+
+	bot.Client = getMockBot(t, func(method string, req *http.Request) interface{} {
+		return tgbotapi.Message{Text: "echo " + TestToken}
+	}).Client
+
+	if _, err := bot.Send(tgbotapi.NewMessage(ChatID, "text")); err != nil {
+		t.Fatal(err)
+	}
+
+	out := logger.out.String()
+	if !strings.Contains(out, "level=DEBUG") || !strings.Contains(out, "method=sendMessage") {
+		t.Errorf("expected debug output, got:\n%s", out)
+	}
+	if strings.Contains(out, TestToken) {
+		t.Errorf("log contains the token:\n%s", out)
+	}
+}
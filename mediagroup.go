@@ -0,0 +1,241 @@
+package tgbotapi
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMediaGroupTimeout is how long a MediaGroupAggregator waits for
+// more messages of an album before emitting it.
+const DefaultMediaGroupTimeout = time.Second
+
+// MediaGroup contains all messages of an album, which Telegram delivers
+// as separate messages sharing a MediaGroupID.
+type MediaGroup struct {
+	ID              string
+	Messages        []Message
+	Photos          [][]PhotoSize
+	Videos          []Video
+	Documents       []Document
+	Audios          []Audio
+	Caption         string
+	CaptionEntities []MessageEntity
+}
+
+// add appends message and its media to the group.
+func (group *MediaGroup) add(message Message) {
+	group.Messages = append(group.Messages, message)
+
+	if message.Photo != nil {
+		group.Photos = append(group.Photos, *message.Photo)
+	}
+	if message.Video != nil {
+		group.Videos = append(group.Videos, *message.Video)
+	}
+	if message.Document != nil {
+		group.Documents = append(group.Documents, *message.Document)
+	}
+	if message.Audio != nil {
+		group.Audios = append(group.Audios, *message.Audio)
+	}
+
+	// Telegram only shows a caption for an album if one item has it.
+	if group.Caption == "" && message.Caption != "" {
+		group.Caption = message.Caption
+		if message.CaptionEntities != nil {
+			group.CaptionEntities = *message.CaptionEntities
+		}
+	}
+}
+
+// MediaGroupAggregator buffers the messages of an album and emits them as a
+// single Update once no more messages of the album arrived for Timeout.
+type MediaGroupAggregator struct {
+	Timeout time.Duration
+
+	initOnce sync.Once
+	stopOnce sync.Once
+	shutdown chan struct{}
+}
+
+// NewMediaGroupAggregator creates a new MediaGroupAggregator.
+//
+// timeout is the quiet period after which an album is considered complete.
+func NewMediaGroupAggregator(timeout time.Duration) *MediaGroupAggregator {
+	return &MediaGroupAggregator{
+		Timeout: timeout,
+	}
+}
+
+// pendingMediaGroup is an album still waiting for more messages.
+type pendingMediaGroup struct {
+	update   Update
+	deadline time.Time
+}
+
+// Aggregate reads updates and returns a channel with the same updates,
+// except that messages and channel posts belonging to an album are
+// replaced by a single Update. It contains the first message of the album
+// and has MediaGroup set.
+//
+// The returned channel has the same buffer size as updates. It is closed
+// after updates is closed and all pending albums have been emitted, or
+// after Stop is called.
+func (a *MediaGroupAggregator) Aggregate(updates UpdatesChannel) UpdatesChannel {
+	ch := make(chan Update, cap(updates))
+	shutdown := a.shutdownChannel()
+
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultMediaGroupTimeout
+	}
+
+	go func() {
+		defer close(ch)
+
+		pending := make(map[string]*pendingMediaGroup)
+		var order []string
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		// send delivers update unless Stop is called while waiting for
+		// the consumer.
+		send := func(update Update) bool {
+			select {
+			case ch <- update:
+				return true
+			case <-shutdown:
+				return false
+			}
+		}
+
+		// stop emits the pending albums which fit in the buffer of ch, so
+		// a consumer which stopped reading does not block the aggregator.
+		stop := func() {
+			for _, key := range order {
+				select {
+				case ch <- pending[key].update:
+				default:
+					return
+				}
+			}
+		}
+
+		// flush emits the albums whose deadline passed, or all of them. It
+		// returns false if Stop was called meanwhile.
+		flush := func(all bool) bool {
+			now := time.Now()
+			remaining := order[:0]
+
+			for i, key := range order {
+				group := pending[key]
+				if !all && now.Before(group.deadline) {
+					remaining = append(remaining, key)
+					continue
+				}
+
+				if !send(group.update) {
+					order = append(remaining, order[i:]...)
+					return false
+				}
+				delete(pending, key)
+			}
+
+			order = remaining
+			return true
+		}
+
+		for {
+			if len(order) > 0 {
+				wait := time.Until(pending[order[0]].deadline)
+				for _, key := range order[1:] {
+					if d := time.Until(pending[key].deadline); d < wait {
+						wait = d
+					}
+				}
+				if wait < 0 {
+					wait = 0
+				}
+				timer.Reset(wait)
+			}
+
+			select {
+			case update, ok := <-updates:
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+
+				if !ok {
+					if !flush(true) {
+						stop()
+					}
+					return
+				}
+
+				message := update.Message
+				if message == nil {
+					message = update.ChannelPost
+				}
+
+				if message == nil || message.MediaGroupID == "" {
+					if !send(update) {
+						stop()
+						return
+					}
+					continue
+				}
+
+				key := message.MediaGroupID
+				if message.Chat != nil {
+					key = strconv.FormatInt(message.Chat.ID, 10) + ":" + key
+				}
+
+				group, exists := pending[key]
+				if !exists {
+					group = &pendingMediaGroup{update: update}
+					group.update.MediaGroup = &MediaGroup{ID: message.MediaGroupID}
+					pending[key] = group
+					order = append(order, key)
+				}
+
+				group.update.MediaGroup.add(*message)
+				group.deadline = time.Now().Add(timeout)
+			case <-timer.C:
+				if !flush(false) {
+					stop()
+					return
+				}
+			case <-shutdown:
+				stop()
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Stop stops aggregating updates and closes the channels returned by
+// Aggregate. Pending albums are only emitted as far as the buffers of
+// those channels have room, so Stop never waits for a consumer which
+// stopped reading.
+func (a *MediaGroupAggregator) Stop() {
+	shutdown := a.shutdownChannel()
+	a.stopOnce.Do(func() {
+		close(shutdown)
+	})
+}
+
+// shutdownChannel returns the channel closed by Stop.
+func (a *MediaGroupAggregator) shutdownChannel() chan struct{} {
+	a.initOnce.Do(func() {
+		a.shutdown = make(chan struct{})
+	})
+
+	return a.shutdown
+}
@@ -0,0 +1,102 @@
+package tgbotapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestMediaGroupAggregator(t *testing.T) {
+	chat := &tgbotapi.Chat{ID: ChatID}
+	in := make(chan tgbotapi.Update, 10)
+
+	in <- tgbotapi.Update{UpdateID: 1, Message: &tgbotapi.Message{Chat: chat, MediaGroupID: "album",
+		Photo: &[]tgbotapi.PhotoSize{{FileID: "photo"}}}}
+	in <- tgbotapi.Update{UpdateID: 2, Message: &tgbotapi.Message{Chat: chat, Text: "text"}}
+	in <- tgbotapi.Update{UpdateID: 3, Message: &tgbotapi.Message{Chat: chat, MediaGroupID: "album",
+		Video: &tgbotapi.Video{FileID: "video"}, Caption: "caption"}}
+	in <- tgbotapi.Update{UpdateID: 4, Message: &tgbotapi.Message{Chat: chat, MediaGroupID: "album",
+		Document: &tgbotapi.Document{FileID: "document"}}}
+
+	out := tgbotapi.NewMediaGroupAggregator(10 * time.Millisecond).Aggregate(in)
+
+	update := <-out
+	if update.UpdateID != 2 || update.MediaGroup != nil {
+		t.Fatal(update)
+	}
+
+	update = <-out
+	group := update.MediaGroup
+	if update.UpdateID != 1 || group == nil {
+		t.Fatal(update)
+	}
+
+	if group.ID != "album" ||
+		len(group.Messages) != 3 ||
+		len(group.Photos) != 1 ||
+		len(group.Videos) != 1 ||
+		len(group.Documents) != 1 ||
+		group.Caption != "caption" {
+		t.Error(group)
+	}
+
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("channel was not closed")
+	}
+}
+
+func TestMediaGroupAggregatorFlushesOnClose(t *testing.T) {
+	in := make(chan tgbotapi.Update, 1)
+	in <- tgbotapi.Update{UpdateID: 1, ChannelPost: &tgbotapi.Message{MediaGroupID: "album"}}
+	close(in)
+
+	out := tgbotapi.NewMediaGroupAggregator(time.Hour).Aggregate(in)
+
+	update, ok := <-out
+	if !ok || update.MediaGroup == nil || len(update.MediaGroup.Messages) != 1 {
+		t.Fail()
+	}
+}
+
+func TestMediaGroupAggregatorStop(t *testing.T) {
+	in := make(chan tgbotapi.Update, 1)
+	in <- tgbotapi.Update{UpdateID: 1, Message: &tgbotapi.Message{MediaGroupID: "album"}}
+
+	aggregator := tgbotapi.NewMediaGroupAggregator(time.Hour)
+	out := aggregator.Aggregate(in)
+
+	// Wait for the album to be pending before stopping.
+	for len(in) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	aggregator.Stop()
+	aggregator.Stop()
+
+	update, ok := <-out
+	if !ok || update.MediaGroup == nil || len(update.MediaGroup.Messages) != 1 {
+		t.Error(update, ok)
+	}
+
+	if _, ok := <-out; ok {
+		t.Error("channel was not closed")
+	}
+}
+
+func TestMediaGroupAggregatorStopWithoutConsumer(t *testing.T) {
+	in := make(chan tgbotapi.Update)
+
+	aggregator := tgbotapi.NewMediaGroupAggregator(time.Hour)
+	out := aggregator.Aggregate(in)
+
+	// Nobody reads the update, so the aggregator waits to deliver it.
+	in <- tgbotapi.Update{UpdateID: 1, Message: &tgbotapi.Message{Text: "text"}}
+	aggregator.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+
+	if update, ok := <-out; ok {
+		t.Error("channel was not closed", update)
+	}
+}
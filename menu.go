@@ -0,0 +1,449 @@
+package tgbotapi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// menuBack is the item index of the back button of a menu node.
+const menuBack = -1
+
+// menuPathSeparator separates the item indexes of the path in menuData.
+const menuPathSeparator = "."
+
+// menuData is the callback data of the buttons of a Menu.
+type menuData struct {
+	// Node is the ID of a node reachable from the root without dynamic
+	// items.
+	Node string
+	// Path are the indexes of the items followed from Node to a node
+	// opened by a dynamic item, separated by dots.
+	Path string
+	Item int
+}
+
+// MenuNode is a screen of a Menu: a message text with a button for each
+// of its items.
+type MenuNode struct {
+	// ID identifies the node in callback data and must be unique within
+	// its Menu.
+	ID string
+
+	Text      string
+	ParseMode string
+	// Render returns the text of the node instead of Text if set.
+	Render func(c *MenuContext) (string, error)
+
+	Items []MenuItem
+	// Dynamic returns additional items shown after Items, if set. It must
+	// return the same items when a button is pressed as when the node was
+	// shown.
+	Dynamic func(c *MenuContext) ([]MenuItem, error)
+	// Columns is the number of buttons per row, one if zero.
+	Columns int
+}
+
+// MenuItem is a button of a MenuNode. Pressing it opens Node, flips
+// Toggle or calls Action, and shows the current node again if it did not
+// open another one.
+type MenuItem struct {
+	Text string
+
+	Node   *MenuNode
+	Toggle *MenuToggle
+	Action func(c *MenuContext) error
+}
+
+// MenuToggle is an on/off setting shown as a MenuItem.
+type MenuToggle struct {
+	Get func(c *MenuContext) (bool, error)
+	Set func(c *MenuContext, on bool) error
+}
+
+// MenuContext is passed to the functions of a MenuNode and its items.
+type MenuContext struct {
+	Menu *Menu
+	Node *MenuNode
+	// Callback is the CallbackQuery being handled. It is nil while
+	// rendering the first message of the menu.
+	Callback *CallbackContext
+	// ChatID is the chat Show sends a new message to if there is no
+	// Callback.
+	ChatID int64
+
+	// origin is the ID of the node path starts from, Node if empty.
+	origin string
+	// path are the indexes of the items followed from origin to Node if
+	// Node was opened by a dynamic item.
+	path []int
+}
+
+// Menu is a tree of MenuNodes shown in a single message. Buttons open
+// submenus or change settings by editing the message.
+//
+// The buttons send callback data encoded by Codec with Prefix. Once the
+// Menu is registered with a CallbackRouter, it handles them by editing the
+// message with an EditMessageTextConfig.
+type Menu struct {
+	Bot *BotAPI
+	// Codec encodes the callback data. Register replaces it with the Codec
+	// of the router, and an unsigned CallbackCodec is used if it is nil.
+	Codec  *CallbackCodec
+	Prefix string
+	Root   *MenuNode
+
+	// BackText is the text of the button returning to the parent node.
+	BackText string
+	// CheckedText and UncheckedText are put in front of the text of
+	// toggles.
+	CheckedText   string
+	UncheckedText string
+
+	nodes   map[string]*MenuNode
+	parents map[string]*MenuNode
+}
+
+// NewMenu creates a new Menu showing root, whose buttons use prefix.
+//
+// It fails if root is nil or two different nodes reachable from root
+// share an ID. Nodes opened by dynamic items are not added to the menu.
+// Their buttons keep the positions of the items leading to them instead,
+// and pressing one calls Dynamic again to find the node, so every message
+// of the menu keeps working on its own.
+func NewMenu(bot *BotAPI, codec *CallbackCodec, prefix string, root *MenuNode) (*Menu, error) {
+	m := &Menu{
+		Bot:           bot,
+		Codec:         codec,
+		Prefix:        prefix,
+		Root:          root,
+		BackText:      "« Back",
+		CheckedText:   "✅ ",
+		UncheckedText: "◻️ ",
+		nodes:         make(map[string]*MenuNode),
+		parents:       make(map[string]*MenuNode),
+	}
+
+	if err := m.add(root, nil); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Register registers the Menu to handle its buttons with router. From
+// then on the buttons are encoded with the Codec of router, so router can
+// always decode them.
+func (m *Menu) Register(router *CallbackRouter) {
+	m.Codec = router.codec()
+	router.Handle(m.Prefix, m.Handle)
+}
+
+// Message creates a message showing the root node of the menu in chatID.
+func (m *Menu) Message(chatID int64) (MessageConfig, error) {
+	return m.message(&MenuContext{Menu: m, Node: m.Root, ChatID: chatID})
+}
+
+// message creates a message showing c.Node in c.ChatID.
+func (m *Menu) message(c *MenuContext) (MessageConfig, error) {
+	text, markup, err := m.render(c)
+	if err != nil {
+		return MessageConfig{}, err
+	}
+
+	msg := NewMessage(c.ChatID, text)
+	msg.ParseMode = c.Node.ParseMode
+	msg.ReplyMarkup = markup
+
+	return msg, nil
+}
+
+// Handle handles a button of the menu.
+func (m *Menu) Handle(c *CallbackContext) error {
+	var data menuData
+	if err := c.Decode(&data); err != nil {
+		return err
+	}
+
+	path, err := parseMenuPath(data.Path)
+	if err != nil {
+		return err
+	}
+
+	if data.Item == menuBack {
+		if len(path) > 0 {
+			path = path[:len(path)-1]
+		} else if parent := m.parents[data.Node]; parent != nil {
+			data.Node = parent.ID
+		}
+	}
+
+	mc, err := m.resolve(c, data.Node, path)
+	if err != nil {
+		return err
+	}
+
+	if data.Item == menuBack {
+		return m.Show(mc)
+	}
+
+	items, err := m.items(mc)
+	if err != nil {
+		return err
+	}
+	if data.Item < 0 || data.Item >= len(items) {
+		return errors.New(ErrMenuItem)
+	}
+	item := items[data.Item]
+
+	switch {
+	case item.Node != nil:
+		if err := m.open(mc, data.Item, item.Node); err != nil {
+			return err
+		}
+	case item.Toggle != nil:
+		on, err := item.Toggle.Get(mc)
+		if err != nil {
+			return err
+		}
+
+		if err := item.Toggle.Set(mc, !on); err != nil {
+			return err
+		}
+	case item.Action != nil:
+		if err := item.Action(mc); err != nil {
+			return err
+		}
+	}
+
+	return m.Show(mc)
+}
+
+// Show edits the message of the CallbackQuery of c to show c.Node, or
+// sends a new message showing it to c.ChatID if c has no Callback.
+//
+// A node opened by a dynamic item can only be shown with the MenuContext
+// passed to the functions of the menu, which knows how it was reached.
+func (m *Menu) Show(c *MenuContext) error {
+	if c.Callback == nil {
+		msg, err := m.message(c)
+		if err != nil {
+			return err
+		}
+
+		_, err = m.Bot.Send(msg)
+		return err
+	}
+
+	text, markup, err := m.render(c)
+	if err != nil {
+		return err
+	}
+
+	edit := EditMessageTextConfig{
+		BaseEdit:  c.Callback.baseEdit(),
+		Text:      text,
+		ParseMode: c.Node.ParseMode,
+	}
+	edit.ReplyMarkup = &markup
+
+	_, err = m.Bot.Request(edit)
+
+	// Actions may leave the node unchanged, and editing a message without
+	// changes fails.
+	if isNotModified(err) {
+		return nil
+	}
+
+	return err
+}
+
+// render returns the text and keyboard of c.Node.
+func (m *Menu) render(c *MenuContext) (string, InlineKeyboardMarkup, error) {
+	text := c.Node.Text
+	if c.Node.Render != nil {
+		var err error
+		if text, err = c.Node.Render(c); err != nil {
+			return "", InlineKeyboardMarkup{}, err
+		}
+	}
+
+	items, err := m.items(c)
+	if err != nil {
+		return "", InlineKeyboardMarkup{}, err
+	}
+
+	codec := m.Codec
+	if codec == nil {
+		codec = &CallbackCodec{}
+	}
+
+	var buttons []InlineKeyboardButton
+	for i, item := range items {
+		label := item.Text
+		if item.Toggle != nil {
+			on, err := item.Toggle.Get(c)
+			if err != nil {
+				return "", InlineKeyboardMarkup{}, err
+			}
+
+			if on {
+				label = m.CheckedText + label
+			} else {
+				label = m.UncheckedText + label
+			}
+		}
+
+		button, err := codec.Button(label, m.Prefix, c.data(i))
+		if err != nil {
+			return "", InlineKeyboardMarkup{}, err
+		}
+
+		buttons = append(buttons, button)
+	}
+
+	keyboard := inlineKeyboardColumns(buttons, c.Node.Columns)
+
+	if len(c.path) > 0 || m.parents[c.Node.ID] != nil {
+		back, err := codec.Button(m.BackText, m.Prefix, c.data(menuBack))
+		if err != nil {
+			return "", InlineKeyboardMarkup{}, err
+		}
+
+		keyboard = append(keyboard, NewInlineKeyboardRow(back))
+	}
+
+	return text, InlineKeyboardMarkup{InlineKeyboard: keyboard}, nil
+}
+
+// items returns the static and dynamic items of c.Node.
+func (m *Menu) items(c *MenuContext) ([]MenuItem, error) {
+	if c.Node.Dynamic == nil {
+		return c.Node.Items, nil
+	}
+
+	dynamic, err := c.Node.Dynamic(c)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]MenuItem, 0, len(c.Node.Items)+len(dynamic))
+	items = append(items, c.Node.Items...)
+
+	return append(items, dynamic...), nil
+}
+
+// resolve returns the context of the node reached by following the items
+// at path from the node with id.
+func (m *Menu) resolve(callback *CallbackContext, id string, path []int) (*MenuContext, error) {
+	node := m.nodes[id]
+	if node == nil {
+		return nil, errors.New(ErrMenuNode)
+	}
+
+	c := &MenuContext{Menu: m, Node: node, Callback: callback}
+
+	for _, i := range path {
+		items, err := m.items(c)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= len(items) || items[i].Node == nil {
+			return nil, errors.New(ErrMenuNode)
+		}
+
+		if err := m.open(c, i, items[i].Node); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// open moves c to node, which is opened by the item of c.Node at index.
+// Nodes which are part of the menu are shown as they are, so links back to
+// the root or other nodes do not move them.
+func (m *Menu) open(c *MenuContext, index int, node *MenuNode) error {
+	if existing, ok := m.nodes[node.ID]; ok {
+		if existing != node {
+			return errors.New(ErrMenuNodeID)
+		}
+
+		c.Node, c.origin, c.path = node, "", nil
+		return nil
+	}
+
+	if c.origin == "" {
+		c.origin = c.Node.ID
+	}
+
+	c.Node = node
+	c.path = append(c.path[:len(c.path):len(c.path)], index)
+
+	return nil
+}
+
+// data returns the callback data of the item of c.Node at index.
+func (c *MenuContext) data(index int) menuData {
+	if len(c.path) == 0 {
+		return menuData{Node: c.Node.ID, Item: index}
+	}
+
+	path := make([]string, len(c.path))
+	for i, item := range c.path {
+		path[i] = strconv.Itoa(item)
+	}
+
+	return menuData{Node: c.origin, Path: strings.Join(path, menuPathSeparator), Item: index}
+}
+
+// parseMenuPath parses the item indexes of the Path of menuData.
+func parseMenuPath(data string) ([]int, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var path []int
+	for _, item := range strings.Split(data, menuPathSeparator) {
+		i, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, errors.New(ErrMenuNode)
+		}
+
+		path = append(path, i)
+	}
+
+	return path, nil
+}
+
+// add adds node below parent and the nodes reachable from it through
+// Items to the menu. Nodes which are part of the menu already keep their
+// parent, so links back to the root or other nodes do not move them.
+func (m *Menu) add(node, parent *MenuNode) error {
+	if node == nil {
+		return errors.New(ErrMenuNodeNil)
+	}
+
+	if existing, ok := m.nodes[node.ID]; ok {
+		if existing != node {
+			return errors.New(ErrMenuNodeID)
+		}
+
+		return nil
+	}
+
+	m.nodes[node.ID] = node
+	if parent != nil {
+		m.parents[node.ID] = parent
+	}
+
+	for _, item := range node.Items {
+		if item.Node != nil {
+			if err := m.add(item.Node, node); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,391 @@
+package tgbotapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+type menuEdit struct {
+	Text   string
+	Markup tgbotapi.InlineKeyboardMarkup
+}
+
+func TestMenu(t *testing.T) {
+	var edits []menuEdit
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		if method == "editMessageText" {
+			edit := menuEdit{Text: req.FormValue("text")}
+			json.Unmarshal([]byte(req.FormValue("reply_markup")), &edit.Markup)
+			edits = append(edits, edit)
+		}
+
+		return true
+	})
+
+	notifications := false
+
+	settings := &tgbotapi.MenuNode{
+		ID:   "settings",
+		Text: "Settings",
+		Items: []tgbotapi.MenuItem{{
+			Text: "Notifications",
+			Toggle: &tgbotapi.MenuToggle{
+				Get: func(c *tgbotapi.MenuContext) (bool, error) {
+					return notifications, nil
+				},
+				Set: func(c *tgbotapi.MenuContext, on bool) error {
+					notifications = on
+					return nil
+				},
+			},
+		}},
+	}
+	root := &tgbotapi.MenuNode{
+		ID: "root",
+		Render: func(c *tgbotapi.MenuContext) (string, error) {
+			if notifications {
+				return "Main menu, notifications on", nil
+			}
+			return "Main menu", nil
+		},
+		Items: []tgbotapi.MenuItem{{Text: "Settings", Node: settings}},
+	}
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	menu, err := tgbotapi.NewMenu(bot, codec, "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+	menu.Register(router)
+
+	msg, err := menu.Message(ChatID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Text != "Main menu" {
+		t.Error(msg.Text)
+	}
+
+	press := func(button tgbotapi.InlineKeyboardButton) {
+		router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:      "query",
+			Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: ChatID}},
+			Data:    *button.CallbackData,
+		}})
+	}
+
+	press(msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard[0][0])
+	if len(edits) != 1 || edits[0].Text != "Settings" {
+		t.Fatal(edits)
+	}
+
+	keyboard := edits[0].Markup.InlineKeyboard
+	if len(keyboard) != 2 || keyboard[0][0].Text != menu.UncheckedText+"Notifications" || keyboard[1][0].Text != menu.BackText {
+		t.Fatal(keyboard)
+	}
+
+	press(keyboard[0][0])
+	if !notifications || edits[1].Markup.InlineKeyboard[0][0].Text != menu.CheckedText+"Notifications" {
+		t.Fatal(edits[1])
+	}
+
+	press(keyboard[1][0])
+	if edits[2].Text != "Main menu, notifications on" {
+		t.Error(edits[2])
+	}
+}
+
+func TestMenuDynamic(t *testing.T) {
+	var texts []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		texts = append(texts, req.FormValue("text"))
+
+		return true
+	})
+
+	root := &tgbotapi.MenuNode{
+		ID:   "root",
+		Text: "Projects",
+		Dynamic: func(c *tgbotapi.MenuContext) ([]tgbotapi.MenuItem, error) {
+			return []tgbotapi.MenuItem{{
+				Text: "Project A",
+				Node: &tgbotapi.MenuNode{ID: "project-a", Text: "Project A details"},
+			}}, nil
+		},
+	}
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	menu, err := tgbotapi.NewMenu(bot, codec, "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+	menu.Register(router)
+
+	msg, _ := menu.Message(ChatID)
+	button := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard[0][0]
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:              "query",
+		InlineMessageID: "inline",
+		Data:            *button.CallbackData,
+	}})
+
+	if len(texts) != 2 || texts[0] != "Project A details" {
+		t.Error(texts)
+	}
+}
+
+func TestMenuDuplicateID(t *testing.T) {
+	root := &tgbotapi.MenuNode{ID: "root", Items: []tgbotapi.MenuItem{
+		{Text: "A", Node: &tgbotapi.MenuNode{ID: "same"}},
+		{Text: "B", Node: &tgbotapi.MenuNode{ID: "same"}},
+	}}
+
+	_, err := tgbotapi.NewMenu(nil, tgbotapi.NewCallbackCodec(nil), "menu", root)
+	if err == nil || err.Error() != tgbotapi.ErrMenuNodeID {
+		t.Error(err)
+	}
+}
+
+func TestMenuNilRoot(t *testing.T) {
+	_, err := tgbotapi.NewMenu(nil, tgbotapi.NewCallbackCodec(nil), "menu", nil)
+	if err == nil || err.Error() != tgbotapi.ErrMenuNodeNil {
+		t.Error(err)
+	}
+}
+
+func TestMenuDynamicLink(t *testing.T) {
+	var edits []menuEdit
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		if method == "editMessageText" {
+			edit := menuEdit{Text: req.FormValue("text")}
+			json.Unmarshal([]byte(req.FormValue("reply_markup")), &edit.Markup)
+			edits = append(edits, edit)
+		}
+
+		return true
+	})
+
+	root := &tgbotapi.MenuNode{ID: "root", Text: "Projects"}
+	root.Dynamic = func(c *tgbotapi.MenuContext) ([]tgbotapi.MenuItem, error) {
+		return []tgbotapi.MenuItem{
+			{Text: "Project", Node: &tgbotapi.MenuNode{ID: "project", Text: "Project"}},
+			{Text: "Home", Node: root},
+		}, nil
+	}
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	menu, err := tgbotapi.NewMenu(bot, codec, "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+	menu.Register(router)
+
+	press := func(button tgbotapi.InlineKeyboardButton) {
+		router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:              "query",
+			InlineMessageID: "inline",
+			Data:            *button.CallbackData,
+		}})
+	}
+
+	msg, _ := menu.Message(ChatID)
+	keyboard := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard
+
+	// Linking back to the root must not give it a back button.
+	press(keyboard[1][0])
+	if len(edits) != 1 || len(edits[0].Markup.InlineKeyboard) != 2 {
+		t.Fatal(edits)
+	}
+
+	press(keyboard[0][0])
+	if len(edits) != 2 || edits[1].Text != "Project" || len(edits[1].Markup.InlineKeyboard) != 1 {
+		t.Fatal(edits)
+	}
+
+	press(edits[1].Markup.InlineKeyboard[0][0])
+	if len(edits) != 3 || edits[2].Text != "Projects" {
+		t.Error(edits)
+	}
+}
+
+func TestMenuDynamicMessages(t *testing.T) {
+	edits := make(map[string]menuEdit)
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		if method == "editMessageText" {
+			edit := menuEdit{Text: req.FormValue("text")}
+			json.Unmarshal([]byte(req.FormValue("reply_markup")), &edit.Markup)
+			edits[req.FormValue("chat_id")] = edit
+		}
+
+		return true
+	})
+
+	chatID := func(c *tgbotapi.MenuContext) int64 {
+		if c.Callback != nil {
+			return c.Callback.Query.Message.Chat.ID
+		}
+		return c.ChatID
+	}
+
+	root := &tgbotapi.MenuNode{
+		ID:   "root",
+		Text: "Projects",
+		Dynamic: func(c *tgbotapi.MenuContext) ([]tgbotapi.MenuItem, error) {
+			id := strconv.FormatInt(chatID(c), 10)
+			return []tgbotapi.MenuItem{{
+				Text: "Project " + id,
+				Node: &tgbotapi.MenuNode{ID: "project-" + id, Text: "Project " + id + " details"},
+			}}, nil
+		},
+	}
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	menu, err := tgbotapi.NewMenu(bot, codec, "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+	menu.Register(router)
+
+	press := func(chatID int64, button tgbotapi.InlineKeyboardButton) {
+		router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:      "query",
+			Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: chatID}},
+			Data:    *button.CallbackData,
+		}})
+	}
+
+	first, _ := menu.Message(1)
+	second, _ := menu.Message(2)
+
+	press(1, first.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard[0][0])
+	press(2, second.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard[0][0])
+	if edits["1"].Text != "Project 1 details" || edits["2"].Text != "Project 2 details" {
+		t.Fatal(edits)
+	}
+
+	// Going back to the projects of the first chat must not break the
+	// buttons still shown in the second one.
+	back := edits["2"].Markup.InlineKeyboard[0][0]
+	press(1, edits["1"].Markup.InlineKeyboard[0][0])
+	press(2, back)
+
+	if edits["1"].Text != "Projects" || edits["2"].Text != "Projects" {
+		t.Error(edits)
+	}
+}
+
+func TestMenuShowMessage(t *testing.T) {
+	var texts []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		if method != "sendMessage" || req.FormValue("chat_id") != strconv.FormatInt(ChatID, 10) {
+			t.Error(method, req.Form)
+		}
+		texts = append(texts, req.FormValue("text"))
+
+		return tgbotapi.Message{MessageID: 1}
+	})
+
+	settings := &tgbotapi.MenuNode{ID: "settings", Text: "Settings"}
+	root := &tgbotapi.MenuNode{ID: "root", Text: "Main menu", Items: []tgbotapi.MenuItem{{Text: "Settings", Node: settings}}}
+
+	menu, err := tgbotapi.NewMenu(bot, tgbotapi.NewCallbackCodec(nil), "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := menu.Show(&tgbotapi.MenuContext{Menu: menu, Node: settings, ChatID: ChatID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(texts) != 1 || texts[0] != "Settings" {
+		t.Error(texts)
+	}
+}
+
+func TestMenuNilCodec(t *testing.T) {
+	settings := &tgbotapi.MenuNode{ID: "settings", Text: "Settings"}
+	root := &tgbotapi.MenuNode{ID: "root", Text: "Main menu", Items: []tgbotapi.MenuItem{{Text: "Settings", Node: settings}}}
+
+	menu, err := tgbotapi.NewMenu(nil, nil, "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := menu.Message(ChatID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyboard := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard
+	if len(keyboard) != 1 || *keyboard[0][0].CallbackData != "menu|root||0" {
+		t.Error(keyboard)
+	}
+}
+
+func TestMenuRouterCodec(t *testing.T) {
+	var texts []string
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+		if method == "editMessageText" {
+			texts = append(texts, req.FormValue("text"))
+		}
+
+		return true
+	})
+
+	settings := &tgbotapi.MenuNode{ID: "settings", Text: "Settings"}
+	root := &tgbotapi.MenuNode{ID: "root", Text: "Main menu", Items: []tgbotapi.MenuItem{{Text: "Settings", Node: settings}}}
+
+	menu, err := tgbotapi.NewMenu(bot, nil, "menu", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, tgbotapi.NewCallbackCodec([]byte("key")))
+	router.OnError = func(c *tgbotapi.CallbackContext, err error) {
+		t.Error(err)
+	}
+	menu.Register(router)
+
+	msg, err := menu.Message(ChatID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "query",
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: ChatID}},
+		Data:    *msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup).InlineKeyboard[0][0].CallbackData,
+	}})
+
+	if len(texts) != 1 || texts[0] != "Settings" {
+		t.Error(texts)
+	}
+}
@@ -0,0 +1,190 @@
+package tgbotapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are the upper bounds in seconds of the request
+// duration histogram of a PrometheusInstrumentation.
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// PrometheusInstrumentation is an Instrumentation which collects metrics
+// per Bot API method and exposes them in the Prometheus text format.
+//
+// It can be served directly as the http.Handler of a metrics endpoint.
+type PrometheusInstrumentation struct {
+	// Namespace prefixes all metric names, "tgbotapi" if empty.
+	Namespace string
+	// Buckets are the upper bounds of the duration histogram,
+	// DefaultDurationBuckets if empty. They must not be changed once
+	// requests were recorded.
+	Buckets []float64
+
+	mu      sync.Mutex
+	methods map[string]*methodMetrics
+}
+
+// methodMetrics are the metrics collected for one method.
+type methodMetrics struct {
+	results       map[string]uint64 // by result label
+	buckets       []uint64
+	durationSum   float64
+	durationCount uint64
+	bytesSent     int64
+	bytesReceived int64
+	retried       uint64
+}
+
+// NewPrometheusInstrumentation creates a new PrometheusInstrumentation.
+func NewPrometheusInstrumentation() *PrometheusInstrumentation {
+	return &PrometheusInstrumentation{}
+}
+
+// StartRequest does nothing, as metrics are only collected once the
+// request is done.
+func (p *PrometheusInstrumentation) StartRequest(ctx context.Context, method string) context.Context {
+	return ctx
+}
+
+// FinishRequest records a finished request.
+func (p *PrometheusInstrumentation) FinishRequest(ctx context.Context, info RequestInfo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.methods == nil {
+		p.methods = make(map[string]*methodMetrics)
+	}
+
+	buckets := p.buckets()
+
+	m, ok := p.methods[info.Method]
+	if !ok {
+		m = &methodMetrics{
+			results: make(map[string]uint64),
+			buckets: make([]uint64, len(buckets)),
+		}
+		p.methods[info.Method] = m
+	}
+
+	m.results[requestOutcome(info)]++
+
+	seconds := info.Duration.Seconds()
+	for i, bound := range buckets {
+		if seconds <= bound {
+			m.buckets[i]++
+		}
+	}
+	m.durationSum += seconds
+	m.durationCount++
+
+	m.bytesSent += info.BytesSent
+	m.bytesReceived += info.BytesReceived
+	if info.Retries > 0 {
+		m.retried++
+	}
+}
+
+// requestOutcome returns the value of the result label for a request.
+func requestOutcome(info RequestInfo) string {
+	switch {
+	case info.Ok:
+		return "ok"
+	case info.ErrorCode != 0:
+		return strconv.Itoa(info.ErrorCode)
+	case info.StatusCode != 0:
+		return strconv.Itoa(info.StatusCode)
+	default:
+		return "error"
+	}
+}
+
+func (p *PrometheusInstrumentation) buckets() []float64 {
+	if len(p.Buckets) == 0 {
+		return DefaultDurationBuckets
+	}
+
+	return p.Buckets
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (p *PrometheusInstrumentation) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	namespace := p.Namespace
+	if namespace == "" {
+		namespace = "tgbotapi"
+	}
+
+	methods := make([]string, 0, len(p.methods))
+	for method := range p.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var b strings.Builder
+
+	writeHeader := func(name, typ, help string) {
+		fmt.Fprintf(&b, "# HELP %s_%s %s\n# TYPE %s_%s %s\n", namespace, name, help, namespace, name, typ)
+	}
+
+	writeHeader("requests_total", "counter", "Requests to the Bot API by method and result.")
+	for _, method := range methods {
+		results := make([]string, 0, len(p.methods[method].results))
+		for result := range p.methods[method].results {
+			results = append(results, result)
+		}
+		sort.Strings(results)
+
+		for _, result := range results {
+			fmt.Fprintf(&b, "%s_requests_total{method=%q,result=%q} %d\n", namespace, method, result, p.methods[method].results[result])
+		}
+	}
+
+	writeHeader("request_duration_seconds", "histogram", "Duration of requests to the Bot API by method.")
+	for _, method := range methods {
+		m := p.methods[method]
+		for i, bound := range p.buckets() {
+			fmt.Fprintf(&b, "%s_request_duration_seconds_bucket{method=%q,le=%q} %d\n", namespace, method, formatFloat(bound), m.buckets[i])
+		}
+		fmt.Fprintf(&b, "%s_request_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n", namespace, method, m.durationCount)
+		fmt.Fprintf(&b, "%s_request_duration_seconds_sum{method=%q} %s\n", namespace, method, formatFloat(m.durationSum))
+		fmt.Fprintf(&b, "%s_request_duration_seconds_count{method=%q} %d\n", namespace, method, m.durationCount)
+	}
+
+	writeHeader("request_bytes_total", "counter", "Bytes sent to the Bot API by method.")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "%s_request_bytes_total{method=%q} %d\n", namespace, method, p.methods[method].bytesSent)
+	}
+
+	writeHeader("response_bytes_total", "counter", "Bytes received from the Bot API by method.")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "%s_response_bytes_total{method=%q} %d\n", namespace, method, p.methods[method].bytesReceived)
+	}
+
+	writeHeader("retried_requests_total", "counter", "Requests to the Bot API which retried a failed one, by method.")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "%s_retried_requests_total{method=%q} %d\n", namespace, method, p.methods[method].retried)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (p *PrometheusInstrumentation) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// formatFloat formats f as a Prometheus sample value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
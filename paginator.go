@@ -0,0 +1,230 @@
+package tgbotapi
+
+import (
+	"strconv"
+)
+
+// DefaultPaginatorPageSize is the number of items a Paginator shows per
+// page if it has no PageSize.
+const DefaultPaginatorPageSize = 5
+
+// PaginatorSource provides the items listed by a Paginator.
+type PaginatorSource interface {
+	// Count returns the total number of items.
+	Count() (int, error)
+	// Buttons returns the buttons for at most limit items starting at
+	// offset.
+	Buttons(offset, limit int) ([]InlineKeyboardButton, error)
+}
+
+// PaginatorButtons is a PaginatorSource listing a fixed set of buttons.
+type PaginatorButtons []InlineKeyboardButton
+
+// Count returns the number of buttons.
+func (b PaginatorButtons) Count() (int, error) {
+	return len(b), nil
+}
+
+// Buttons returns at most limit buttons starting at offset. Appending to
+// them does not change b.
+func (b PaginatorButtons) Buttons(offset, limit int) ([]InlineKeyboardButton, error) {
+	if offset >= len(b) {
+		return nil, nil
+	}
+
+	end := offset + limit
+	if end > len(b) {
+		end = len(b)
+	}
+
+	return b[offset:end:end], nil
+}
+
+// paginatorData is the callback data of the navigation buttons of a
+// Paginator.
+type paginatorData struct {
+	Page    int
+	Current bool
+}
+
+// Paginator renders the items of a PaginatorSource one page at a time as
+// an inline keyboard, followed by a row of buttons to navigate between
+// pages.
+//
+// The navigation buttons send callback data encoded by Codec with Prefix.
+// Once the Paginator is registered with a CallbackRouter, it handles them
+// by editing the keyboard of the message to show the requested page.
+type Paginator struct {
+	Bot *BotAPI
+	// Codec encodes the callback data. Register replaces it with the Codec
+	// of the router, and an unsigned CallbackCodec is used if it is nil.
+	Codec  *CallbackCodec
+	Prefix string
+	Source PaginatorSource
+
+	// PageSize is the number of items per page,
+	// DefaultPaginatorPageSize if zero.
+	PageSize int
+	// Columns is the number of item buttons per row, one if zero.
+	Columns int
+
+	// PreviousText and NextText are the texts of the navigation buttons.
+	PreviousText string
+	NextText     string
+}
+
+// NewPaginator creates a new Paginator listing the items of source, whose
+// navigation buttons use prefix.
+func NewPaginator(bot *BotAPI, codec *CallbackCodec, prefix string, source PaginatorSource) *Paginator {
+	return &Paginator{
+		Bot:          bot,
+		Codec:        codec,
+		Prefix:       prefix,
+		Source:       source,
+		PreviousText: "«",
+		NextText:     "»",
+	}
+}
+
+// Register registers the Paginator to handle its navigation buttons with
+// router. From then on the buttons are encoded with the Codec of router,
+// so router can always decode them.
+func (p *Paginator) Register(router *CallbackRouter) {
+	p.Codec = router.codec()
+	router.Handle(p.Prefix, p.Handle)
+}
+
+// Markup renders page, counting from zero, as an inline keyboard. Pages
+// out of range show the closest existing page.
+func (p *Paginator) Markup(page int) (InlineKeyboardMarkup, error) {
+	count, err := p.Source.Count()
+	if err != nil {
+		return InlineKeyboardMarkup{}, err
+	}
+
+	pageSize := p.pageSize()
+	pages := (count + pageSize - 1) / pageSize
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	buttons, err := p.Source.Buttons(page*pageSize, pageSize)
+	if err != nil {
+		return InlineKeyboardMarkup{}, err
+	}
+
+	keyboard := inlineKeyboardColumns(buttons, p.Columns)
+
+	if pages > 1 {
+		navigation, err := p.navigation(page, pages)
+		if err != nil {
+			return InlineKeyboardMarkup{}, err
+		}
+
+		keyboard = append(keyboard, navigation)
+	}
+
+	return InlineKeyboardMarkup{InlineKeyboard: keyboard}, nil
+}
+
+// Handle shows the page requested by a navigation button by editing the
+// keyboard of its message.
+func (p *Paginator) Handle(c *CallbackContext) error {
+	var data paginatorData
+	if err := c.Decode(&data); err != nil {
+		return err
+	}
+
+	// The page indicator only shows the current page, and editing a
+	// message without changes fails.
+	if data.Current {
+		return nil
+	}
+
+	markup, err := p.Markup(data.Page)
+	if err != nil {
+		return err
+	}
+
+	edit := EditMessageReplyMarkupConfig{BaseEdit: c.baseEdit()}
+	edit.ReplyMarkup = &markup
+
+	// Pressing a button twice before the message was edited requests the
+	// page which is already shown.
+	_, err = p.Bot.Request(edit)
+	if isNotModified(err) {
+		return nil
+	}
+
+	return err
+}
+
+// navigation renders the navigation row for page out of pages.
+func (p *Paginator) navigation(page, pages int) ([]InlineKeyboardButton, error) {
+	codec := p.Codec
+	if codec == nil {
+		codec = &CallbackCodec{}
+	}
+
+	var row []InlineKeyboardButton
+
+	if page > 0 {
+		previous, err := codec.Button(p.PreviousText, p.Prefix, paginatorData{Page: page - 1})
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, previous)
+	}
+
+	indicator := strconv.Itoa(page+1) + "/" + strconv.Itoa(pages)
+	current, err := codec.Button(indicator, p.Prefix, paginatorData{Page: page, Current: true})
+	if err != nil {
+		return nil, err
+	}
+	row = append(row, current)
+
+	if page < pages-1 {
+		next, err := codec.Button(p.NextText, p.Prefix, paginatorData{Page: page + 1})
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, next)
+	}
+
+	return row, nil
+}
+
+// pageSize returns the number of items per page.
+func (p *Paginator) pageSize() int {
+	if p.PageSize <= 0 {
+		return DefaultPaginatorPageSize
+	}
+
+	return p.PageSize
+}
+
+// inlineKeyboardColumns arranges buttons in rows of columns buttons, or
+// one button per row if columns is zero.
+func inlineKeyboardColumns(buttons []InlineKeyboardButton, columns int) [][]InlineKeyboardButton {
+	if columns <= 0 {
+		columns = 1
+	}
+
+	keyboard := [][]InlineKeyboardButton{}
+	for len(buttons) > 0 {
+		n := columns
+		if n > len(buttons) {
+			n = len(buttons)
+		}
+
+		keyboard = append(keyboard, buttons[:n])
+		buttons = buttons[n:]
+	}
+
+	return keyboard
+}
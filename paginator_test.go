@@ -0,0 +1,175 @@
+package tgbotapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestPaginator(t *testing.T) {
+	var edits []tgbotapi.InlineKeyboardMarkup
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		req.ParseForm()
+
+		if method == "editMessageReplyMarkup" {
+			if req.FormValue("chat_id") != "10" || req.FormValue("message_id") != "20" {
+				t.Error(req.Form)
+			}
+
+			var markup tgbotapi.InlineKeyboardMarkup
+			json.Unmarshal([]byte(req.FormValue("reply_markup")), &markup)
+			edits = append(edits, markup)
+		}
+
+		return true
+	})
+
+	var items tgbotapi.PaginatorButtons
+	for i := 0; i < 7; i++ {
+		items = append(items, tgbotapi.NewInlineKeyboardButtonData("Item "+strconv.Itoa(i), "item"))
+	}
+
+	codec := tgbotapi.NewCallbackCodec(nil)
+	paginator := tgbotapi.NewPaginator(bot, codec, "page", items)
+	paginator.PageSize = 3
+	paginator.Columns = 2
+
+	markup, err := paginator.Markup(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two rows of items and the navigation row.
+	if len(markup.InlineKeyboard) != 3 || len(markup.InlineKeyboard[0]) != 2 || len(markup.InlineKeyboard[1]) != 1 {
+		t.Fatal(markup.InlineKeyboard)
+	}
+	navigation := markup.InlineKeyboard[2]
+	if len(navigation) != 2 || navigation[0].Text != "1/3" || navigation[1].Text != "»" {
+		t.Fatal(navigation)
+	}
+
+	router := tgbotapi.NewCallbackRouter(bot, codec)
+	paginator.Register(router)
+
+	message := &tgbotapi.Message{MessageID: 20, Chat: &tgbotapi.Chat{ID: 10}}
+	for _, button := range []tgbotapi.InlineKeyboardButton{navigation[1], navigation[0]} {
+		router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:      "query",
+			Message: message,
+			Data:    *button.CallbackData,
+		}})
+	}
+
+	// Pressing the page indicator does not edit the message.
+	if len(edits) != 1 {
+		t.Fatal(edits)
+	}
+
+	navigation = edits[0].InlineKeyboard[len(edits[0].InlineKeyboard)-1]
+	if len(navigation) != 3 || navigation[1].Text != "2/3" || edits[0].InlineKeyboard[0][0].Text != "Item 3" {
+		t.Error(edits[0].InlineKeyboard)
+	}
+}
+
+func TestPaginatorSinglePage(t *testing.T) {
+	paginator := tgbotapi.NewPaginator(nil, tgbotapi.NewCallbackCodec(nil), "page", tgbotapi.PaginatorButtons{
+		tgbotapi.NewInlineKeyboardButtonData("Only", "item"),
+	})
+
+	markup, err := paginator.Markup(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(markup.InlineKeyboard) != 1 || markup.InlineKeyboard[0][0].Text != "Only" {
+		t.Error(markup.InlineKeyboard)
+	}
+}
+
+func TestPaginatorButtonsCopy(t *testing.T) {
+	source := tgbotapi.PaginatorButtons{
+		tgbotapi.NewInlineKeyboardButtonData("a", "a"),
+		tgbotapi.NewInlineKeyboardButtonData("b", "b"),
+	}
+
+	buttons, _ := source.Buttons(0, 1)
+	_ = append(buttons, tgbotapi.NewInlineKeyboardButtonData("c", "c"))
+
+	if source[1].Text != "b" {
+		t.Error("appending to a page changed the source")
+	}
+}
+
+func TestPaginatorNilCodec(t *testing.T) {
+	var items tgbotapi.PaginatorButtons
+	for i := 0; i < 4; i++ {
+		items = append(items, tgbotapi.NewInlineKeyboardButtonData("Item "+strconv.Itoa(i), "item"))
+	}
+
+	paginator := tgbotapi.NewPaginator(nil, nil, "page", items)
+	paginator.PageSize = 2
+
+	markup, err := paginator.Markup(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := tgbotapi.NewCallbackCodec(nil).Encode("page", struct {
+		Page    int
+		Current bool
+	}{Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	navigation := markup.InlineKeyboard[len(markup.InlineKeyboard)-1]
+	if len(navigation) != 2 || *navigation[1].CallbackData != next {
+		t.Error(navigation)
+	}
+}
+
+func TestPaginatorRouterCodec(t *testing.T) {
+	edits := 0
+
+	bot := getMockBot(t, func(method string, req *http.Request) interface{} {
+		if method == "editMessageReplyMarkup" {
+			edits++
+		}
+
+		return true
+	})
+
+	var items tgbotapi.PaginatorButtons
+	for i := 0; i < 4; i++ {
+		items = append(items, tgbotapi.NewInlineKeyboardButtonData("Item "+strconv.Itoa(i), "item"))
+	}
+
+	paginator := tgbotapi.NewPaginator(bot, nil, "page", items)
+	paginator.PageSize = 2
+
+	router := tgbotapi.NewCallbackRouter(bot, tgbotapi.NewCallbackCodec([]byte("key")))
+	router.OnError = func(c *tgbotapi.CallbackContext, err error) {
+		t.Error(err)
+	}
+	paginator.Register(router)
+
+	markup, err := paginator.Markup(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	navigation := markup.InlineKeyboard[len(markup.InlineKeyboard)-1]
+	router.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "query",
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: ChatID}},
+		Data:    *navigation[1].CallbackData,
+	}})
+
+	if edits != 1 {
+		t.Errorf("expected the page to change, got %d edits", edits)
+	}
+}
@@ -0,0 +1,87 @@
+package tgbotapi
+
+import (
+	"context"
+)
+
+// Span is a single traced request. Its methods match those of an
+// OpenTelemetry span closely enough to wrap one in a few lines.
+type Span interface {
+	// SetAttribute sets an attribute, where value is a string, int, int64
+	// or bool.
+	SetAttribute(key string, value interface{})
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// StartSpanFunc starts a Span named name as a child of any span in ctx and
+// returns a context containing the new span.
+type StartSpanFunc func(ctx context.Context, name string) (context.Context, Span)
+
+// Attribute keys set on spans by a SpanInstrumentation. They follow the
+// OpenTelemetry semantic conventions where they exist.
+const (
+	SpanAttributeSystem       = "rpc.system"
+	SpanAttributeMethod       = "rpc.method"
+	SpanAttributeStatusCode   = "http.response.status_code"
+	SpanAttributeRequestSize  = "http.request.body.size"
+	SpanAttributeResponseSize = "http.response.body.size"
+	SpanAttributeErrorCode    = "telegram.error_code"
+	SpanAttributeRetryCount   = "http.request.resend_count"
+	SpanAttributeUpload       = "telegram.upload"
+)
+
+// SpanInstrumentation is an Instrumentation which traces every request as
+// a Span, for example with OpenTelemetry.
+type SpanInstrumentation struct {
+	StartSpan StartSpanFunc
+}
+
+type spanKey struct{}
+
+// NewSpanInstrumentation creates a new SpanInstrumentation, which starts
+// spans with startSpan.
+func NewSpanInstrumentation(startSpan StartSpanFunc) *SpanInstrumentation {
+	return &SpanInstrumentation{
+		StartSpan: startSpan,
+	}
+}
+
+// StartRequest starts the span of a request.
+func (s *SpanInstrumentation) StartRequest(ctx context.Context, method string) context.Context {
+	ctx, span := s.StartSpan(ctx, "telegram "+method)
+
+	span.SetAttribute(SpanAttributeSystem, "telegram")
+	span.SetAttribute(SpanAttributeMethod, method)
+
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// FinishRequest sets the attributes of a request on its span and ends it.
+func (s *SpanInstrumentation) FinishRequest(ctx context.Context, info RequestInfo) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+
+	if info.StatusCode != 0 {
+		span.SetAttribute(SpanAttributeStatusCode, info.StatusCode)
+	}
+	if info.ErrorCode != 0 {
+		span.SetAttribute(SpanAttributeErrorCode, info.ErrorCode)
+	}
+	if info.Retries != 0 {
+		span.SetAttribute(SpanAttributeRetryCount, info.Retries)
+	}
+	span.SetAttribute(SpanAttributeRequestSize, info.BytesSent)
+	span.SetAttribute(SpanAttributeResponseSize, info.BytesReceived)
+	span.SetAttribute(SpanAttributeUpload, info.Upload)
+
+	if info.Err != nil {
+		span.RecordError(info.Err)
+	}
+
+	span.End()
+}
@@ -37,6 +37,10 @@ type Update struct {
 	CallbackQuery      *CallbackQuery      `json:"callback_query"`
 	ShippingQuery      *ShippingQuery      `json:"shipping_query"`
 	PreCheckoutQuery   *PreCheckoutQuery   `json:"pre_checkout_query"`
+
+	// MediaGroup is only set on updates emitted by a MediaGroupAggregator,
+	// and contains every message of an album.
+	MediaGroup *MediaGroup `json:"-"`
 }
 
 // UpdatesChannel is the channel for getting updates.
@@ -51,12 +55,17 @@ func (ch UpdatesChannel) Clear() {
 
 // User is a user on Telegram.
 type User struct {
-	ID           int    `json:"id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`     // optional
-	UserName     string `json:"username"`      // optional
-	LanguageCode string `json:"language_code"` // optional
-	IsBot        bool   `json:"is_bot"`        // optional
+	ID                      int64  `json:"id"`
+	FirstName               string `json:"first_name"`
+	LastName                string `json:"last_name"`                   // optional
+	UserName                string `json:"username"`                    // optional
+	LanguageCode            string `json:"language_code"`               // optional
+	IsBot                   bool   `json:"is_bot"`                      // optional
+	IsPremium               bool   `json:"is_premium"`                  // optional
+	AddedToAttachmentMenu   bool   `json:"added_to_attachment_menu"`    // optional
+	CanJoinGroups           bool   `json:"can_join_groups"`             // optional, returned only in getMe
+	CanReadAllGroupMessages bool   `json:"can_read_all_group_messages"` // optional, returned only in getMe
+	SupportsInlineQueries   bool   `json:"supports_inline_queries"`     // optional, returned only in getMe
 }
 
 // String displays a simple text version of a user.
@@ -84,22 +93,65 @@ type GroupChat struct {
 
 // ChatPhoto represents a chat photo.
 type ChatPhoto struct {
-	SmallFileID string `json:"small_file_id"`
-	BigFileID   string `json:"big_file_id"`
+	SmallFileID       string `json:"small_file_id"`
+	SmallFileUniqueID string `json:"small_file_unique_id"`
+	BigFileID         string `json:"big_file_id"`
+	BigFileUniqueID   string `json:"big_file_unique_id"`
+}
+
+// ChatPermissions describes actions that a non-administrator user is
+// allowed to take in a chat.
+type ChatPermissions struct {
+	CanSendMessages       bool `json:"can_send_messages"`         // optional
+	CanSendAudios         bool `json:"can_send_audios"`           // optional
+	CanSendDocuments      bool `json:"can_send_documents"`        // optional
+	CanSendPhotos         bool `json:"can_send_photos"`           // optional
+	CanSendVideos         bool `json:"can_send_videos"`           // optional
+	CanSendVideoNotes     bool `json:"can_send_video_notes"`      // optional
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes"`      // optional
+	CanSendPolls          bool `json:"can_send_polls"`            // optional
+	CanSendOtherMessages  bool `json:"can_send_other_messages"`   // optional
+	CanAddWebPagePreviews bool `json:"can_add_web_page_previews"` // optional
+	CanChangeInfo         bool `json:"can_change_info"`           // optional
+	CanInviteUsers        bool `json:"can_invite_users"`          // optional
+	CanPinMessages        bool `json:"can_pin_messages"`          // optional
+	CanManageTopics       bool `json:"can_manage_topics"`         // optional
+}
+
+// ChatLocation represents a location to which a chat is connected.
+type ChatLocation struct {
+	Location Location `json:"location"`
+	Address  string   `json:"address"`
 }
 
 // Chat contains information about the place a message was sent.
 type Chat struct {
-	ID                  int64      `json:"id"`
-	Type                string     `json:"type"`
-	Title               string     `json:"title"`                          // optional
-	UserName            string     `json:"username"`                       // optional
-	FirstName           string     `json:"first_name"`                     // optional
-	LastName            string     `json:"last_name"`                      // optional
-	AllMembersAreAdmins bool       `json:"all_members_are_administrators"` // optional
-	Photo               *ChatPhoto `json:"photo"`
-	Description         string     `json:"description,omitempty"` // optional
-	InviteLink          string     `json:"invite_link,omitempty"` // optional
+	ID                                 int64            `json:"id"`
+	Type                               string           `json:"type"`
+	Title                              string           `json:"title"`                          // optional
+	UserName                           string           `json:"username"`                       // optional
+	FirstName                          string           `json:"first_name"`                     // optional
+	LastName                           string           `json:"last_name"`                      // optional
+	IsForum                            bool             `json:"is_forum"`                       // optional
+	AllMembersAreAdmins                bool             `json:"all_members_are_administrators"` // optional
+	Photo                              *ChatPhoto       `json:"photo"`
+	ActiveUsernames                    []string         `json:"active_usernames,omitempty"`                        // optional
+	Bio                                string           `json:"bio,omitempty"`                                     // optional
+	HasPrivateForwards                 bool             `json:"has_private_forwards,omitempty"`                    // optional
+	HasRestrictedVoiceAndVideoMessages bool             `json:"has_restricted_voice_and_video_messages,omitempty"` // optional
+	JoinToSendMessages                 bool             `json:"join_to_send_messages,omitempty"`                   // optional
+	JoinByRequest                      bool             `json:"join_by_request,omitempty"`                         // optional
+	Description                        string           `json:"description,omitempty"`                             // optional
+	InviteLink                         string           `json:"invite_link,omitempty"`                             // optional
+	PinnedMessage                      *Message         `json:"pinned_message,omitempty"`                          // optional
+	Permissions                        *ChatPermissions `json:"permissions,omitempty"`                             // optional
+	SlowModeDelay                      int              `json:"slow_mode_delay,omitempty"`                         // optional
+	MessageAutoDeleteTime              int              `json:"message_auto_delete_time,omitempty"`                // optional
+	HasProtectedContent                bool             `json:"has_protected_content,omitempty"`                   // optional
+	StickerSetName                     string           `json:"sticker_set_name,omitempty"`                        // optional
+	CanSetStickerSet                   bool             `json:"can_set_sticker_set,omitempty"`                     // optional
+	LinkedChatID                       int64            `json:"linked_chat_id,omitempty"`                          // optional
+	Location                           *ChatLocation    `json:"location,omitempty"`                                // optional
 }
 
 // IsPrivate returns if the Chat is a private conversation.
@@ -130,45 +182,65 @@ func (c Chat) ChatConfig() ChatConfig {
 // Message is returned by almost every request, and contains data about
 // almost anything.
 type Message struct {
-	MessageID             int                `json:"message_id"`
-	From                  *User              `json:"from"` // optional
-	Date                  int                `json:"date"`
-	Chat                  *Chat              `json:"chat"`
-	ForwardFrom           *User              `json:"forward_from"`            // optional
-	ForwardFromChat       *Chat              `json:"forward_from_chat"`       // optional
-	ForwardFromMessageID  int                `json:"forward_from_message_id"` // optional
-	ForwardDate           int                `json:"forward_date"`            // optional
-	ReplyToMessage        *Message           `json:"reply_to_message"`        // optional
-	EditDate              int                `json:"edit_date"`               // optional
-	Text                  string             `json:"text"`                    // optional
-	Entities              *[]MessageEntity   `json:"entities"`                // optional
-	Audio                 *Audio             `json:"audio"`                   // optional
-	Document              *Document          `json:"document"`                // optional
-	Animation             *ChatAnimation     `json:"animation"`               // optional
-	Game                  *Game              `json:"game"`                    // optional
-	Photo                 *[]PhotoSize       `json:"photo"`                   // optional
-	Sticker               *Sticker           `json:"sticker"`                 // optional
-	Video                 *Video             `json:"video"`                   // optional
-	VideoNote             *VideoNote         `json:"video_note"`              // optional
-	Voice                 *Voice             `json:"voice"`                   // optional
-	Caption               string             `json:"caption"`                 // optional
-	Contact               *Contact           `json:"contact"`                 // optional
-	Location              *Location          `json:"location"`                // optional
-	Venue                 *Venue             `json:"venue"`                   // optional
-	NewChatMembers        *[]User            `json:"new_chat_members"`        // optional
-	LeftChatMember        *User              `json:"left_chat_member"`        // optional
-	NewChatTitle          string             `json:"new_chat_title"`          // optional
-	NewChatPhoto          *[]PhotoSize       `json:"new_chat_photo"`          // optional
-	DeleteChatPhoto       bool               `json:"delete_chat_photo"`       // optional
-	GroupChatCreated      bool               `json:"group_chat_created"`      // optional
-	SuperGroupChatCreated bool               `json:"supergroup_chat_created"` // optional
-	ChannelChatCreated    bool               `json:"channel_chat_created"`    // optional
-	MigrateToChatID       int64              `json:"migrate_to_chat_id"`      // optional
-	MigrateFromChatID     int64              `json:"migrate_from_chat_id"`    // optional
-	PinnedMessage         *Message           `json:"pinned_message"`          // optional
-	Invoice               *Invoice           `json:"invoice"`                 // optional
-	SuccessfulPayment     *SuccessfulPayment `json:"successful_payment"`      // optional
-	PassportData          *PassportData      `json:"passport_data,omitempty"` // optional
+	MessageID                     int                            `json:"message_id"`
+	MessageThreadID               int                            `json:"message_thread_id"` // optional
+	From                          *User                          `json:"from"`              // optional
+	SenderChat                    *Chat                          `json:"sender_chat"`       // optional
+	Date                          int                            `json:"date"`
+	Chat                          *Chat                          `json:"chat"`
+	ForwardFrom                   *User                          `json:"forward_from"`                      // optional
+	ForwardFromChat               *Chat                          `json:"forward_from_chat"`                 // optional
+	ForwardFromMessageID          int                            `json:"forward_from_message_id"`           // optional
+	ForwardSignature              string                         `json:"forward_signature"`                 // optional
+	ForwardSenderName             string                         `json:"forward_sender_name"`               // optional
+	ForwardDate                   int                            `json:"forward_date"`                      // optional
+	IsTopicMessage                bool                           `json:"is_topic_message"`                  // optional
+	IsAutomaticForward            bool                           `json:"is_automatic_forward"`              // optional
+	ReplyToMessage                *Message                       `json:"reply_to_message"`                  // optional
+	ViaBot                        *User                          `json:"via_bot"`                           // optional
+	EditDate                      int                            `json:"edit_date"`                         // optional
+	HasProtectedContent           bool                           `json:"has_protected_content"`             // optional
+	MediaGroupID                  string                         `json:"media_group_id"`                    // optional
+	AuthorSignature               string                         `json:"author_signature"`                  // optional
+	Text                          string                         `json:"text"`                              // optional
+	Entities                      *[]MessageEntity               `json:"entities"`                          // optional
+	Audio                         *Audio                         `json:"audio"`                             // optional
+	Document                      *Document                      `json:"document"`                          // optional
+	Animation                     *ChatAnimation                 `json:"animation"`                         // optional
+	Game                          *Game                          `json:"game"`                              // optional
+	Photo                         *[]PhotoSize                   `json:"photo"`                             // optional
+	Sticker                       *Sticker                       `json:"sticker"`                           // optional
+	Video                         *Video                         `json:"video"`                             // optional
+	VideoNote                     *VideoNote                     `json:"video_note"`                        // optional
+	Voice                         *Voice                         `json:"voice"`                             // optional
+	Caption                       string                         `json:"caption"`                           // optional
+	CaptionEntities               *[]MessageEntity               `json:"caption_entities"`                  // optional
+	HasMediaSpoiler               bool                           `json:"has_media_spoiler"`                 // optional
+	Contact                       *Contact                       `json:"contact"`                           // optional
+	Dice                          *Dice                          `json:"dice"`                              // optional
+	Poll                          *Poll                          `json:"poll"`                              // optional
+	Location                      *Location                      `json:"location"`                          // optional
+	Venue                         *Venue                         `json:"venue"`                             // optional
+	NewChatMembers                *[]User                        `json:"new_chat_members"`                  // optional
+	LeftChatMember                *User                          `json:"left_chat_member"`                  // optional
+	NewChatTitle                  string                         `json:"new_chat_title"`                    // optional
+	NewChatPhoto                  *[]PhotoSize                   `json:"new_chat_photo"`                    // optional
+	DeleteChatPhoto               bool                           `json:"delete_chat_photo"`                 // optional
+	GroupChatCreated              bool                           `json:"group_chat_created"`                // optional
+	SuperGroupChatCreated         bool                           `json:"supergroup_chat_created"`           // optional
+	ChannelChatCreated            bool                           `json:"channel_chat_created"`              // optional
+	MessageAutoDeleteTimerChanged *MessageAutoDeleteTimerChanged `json:"message_auto_delete_timer_changed"` // optional
+	MigrateToChatID               int64                          `json:"migrate_to_chat_id"`                // optional
+	MigrateFromChatID             int64                          `json:"migrate_from_chat_id"`              // optional
+	PinnedMessage                 *Message                       `json:"pinned_message"`                    // optional
+	Invoice                       *Invoice                       `json:"invoice"`                           // optional
+	SuccessfulPayment             *SuccessfulPayment             `json:"successful_payment"`                // optional
+	ConnectedWebsite              string                         `json:"connected_website"`                 // optional
+	UserShared                    *UserShared                    `json:"user_shared,omitempty"`             // optional
+	ChatShared                    *ChatShared                    `json:"chat_shared,omitempty"`             // optional
+	WebAppData                    *WebAppData                    `json:"web_app_data,omitempty"`            // optional
+	PassportData                  *PassportData                  `json:"passport_data,omitempty"`           // optional
+	ReplyMarkup                   *InlineKeyboardMarkup          `json:"reply_markup"`                      // optional
 }
 
 // Time converts the message timestamp into a Time.
@@ -241,11 +313,12 @@ func (m *Message) CommandArguments() string {
 
 // MessageEntity contains information about data in a Message.
 type MessageEntity struct {
-	Type   string `json:"type"`
-	Offset int    `json:"offset"`
-	Length int    `json:"length"`
-	URL    string `json:"url"`  // optional
-	User   *User  `json:"user"` // optional
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	URL      string `json:"url,omitempty"`      // optional
+	User     *User  `json:"user,omitempty"`     // optional
+	Language string `json:"language,omitempty"` // optional
 }
 
 // ParseURL attempts to parse a URL contained within a MessageEntity.
@@ -257,82 +330,125 @@ func (entity MessageEntity) ParseURL() (*url.URL, error) {
 	return url.Parse(entity.URL)
 }
 
+// MessageAutoDeleteTimerChanged represents a service message about a change
+// in auto-delete timer settings.
+type MessageAutoDeleteTimerChanged struct {
+	MessageAutoDeleteTime int `json:"message_auto_delete_time"`
+}
+
+// Dice represents an animated emoji with a random value.
+type Dice struct {
+	Emoji string `json:"emoji"`
+	Value int    `json:"value"`
+}
+
+// PollOption contains information about one answer option in a poll.
+type PollOption struct {
+	Text       string `json:"text"`
+	VoterCount int    `json:"voter_count"`
+}
+
+// Poll contains information about a poll.
+type Poll struct {
+	ID                    string          `json:"id"`
+	Question              string          `json:"question"`
+	Options               []PollOption    `json:"options"`
+	TotalVoterCount       int             `json:"total_voter_count"`
+	IsClosed              bool            `json:"is_closed"`
+	IsAnonymous           bool            `json:"is_anonymous"`
+	Type                  string          `json:"type"`
+	AllowsMultipleAnswers bool            `json:"allows_multiple_answers"`
+	CorrectOptionID       *int            `json:"correct_option_id,omitempty"`    // optional
+	Explanation           string          `json:"explanation,omitempty"`          // optional
+	ExplanationEntities   []MessageEntity `json:"explanation_entities,omitempty"` // optional
+	OpenPeriod            int             `json:"open_period,omitempty"`          // optional
+	CloseDate             int             `json:"close_date,omitempty"`           // optional
+}
+
 // PhotoSize contains information about photos.
 type PhotoSize struct {
-	FileID   string `json:"file_id"`
-	Width    int    `json:"width"`
-	Height   int    `json:"height"`
-	FileSize int    `json:"file_size"` // optional
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int    `json:"file_size"` // optional
 }
 
 // Audio contains information about audio.
 type Audio struct {
-	FileID    string `json:"file_id"`
-	Duration  int    `json:"duration"`
-	Performer string `json:"performer"` // optional
-	Title     string `json:"title"`     // optional
-	MimeType  string `json:"mime_type"` // optional
-	FileSize  int    `json:"file_size"` // optional
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"`
+	Performer    string `json:"performer"` // optional
+	Title        string `json:"title"`     // optional
+	MimeType     string `json:"mime_type"` // optional
+	FileSize     int    `json:"file_size"` // optional
 }
 
 // Document contains information about a document.
 type Document struct {
-	FileID    string     `json:"file_id"`
-	Thumbnail *PhotoSize `json:"thumb"`     // optional
-	FileName  string     `json:"file_name"` // optional
-	MimeType  string     `json:"mime_type"` // optional
-	FileSize  int        `json:"file_size"` // optional
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Thumbnail    *PhotoSize `json:"thumb"`     // optional
+	FileName     string     `json:"file_name"` // optional
+	MimeType     string     `json:"mime_type"` // optional
+	FileSize     int        `json:"file_size"` // optional
 }
 
 // Sticker contains information about a sticker.
 type Sticker struct {
-	FileID    string     `json:"file_id"`
-	Width     int        `json:"width"`
-	Height    int        `json:"height"`
-	Thumbnail *PhotoSize `json:"thumb"`     // optional
-	Emoji     string     `json:"emoji"`     // optional
-	FileSize  int        `json:"file_size"` // optional
-	SetName   string     `json:"set_name"`  // optional
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Thumbnail    *PhotoSize `json:"thumb"`     // optional
+	Emoji        string     `json:"emoji"`     // optional
+	FileSize     int        `json:"file_size"` // optional
+	SetName      string     `json:"set_name"`  // optional
 }
 
 // ChatAnimation contains information about an animation.
 type ChatAnimation struct {
-	FileID    string     `json:"file_id"`
-	Width     int        `json:"width"`
-	Height    int        `json:"height"`
-	Duration  int        `json:"duration"`
-	Thumbnail *PhotoSize `json:"thumb"`     // optional
-	FileName  string     `json:"file_name"` // optional
-	MimeType  string     `json:"mime_type"` // optional
-	FileSize  int        `json:"file_size"` // optional
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumb"`     // optional
+	FileName     string     `json:"file_name"` // optional
+	MimeType     string     `json:"mime_type"` // optional
+	FileSize     int        `json:"file_size"` // optional
 }
 
 // Video contains information about a video.
 type Video struct {
-	FileID    string     `json:"file_id"`
-	Width     int        `json:"width"`
-	Height    int        `json:"height"`
-	Duration  int        `json:"duration"`
-	Thumbnail *PhotoSize `json:"thumb"`     // optional
-	MimeType  string     `json:"mime_type"` // optional
-	FileSize  int        `json:"file_size"` // optional
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumb"`     // optional
+	MimeType     string     `json:"mime_type"` // optional
+	FileSize     int        `json:"file_size"` // optional
 }
 
 // VideoNote contains information about a video.
 type VideoNote struct {
-	FileID    string     `json:"file_id"`
-	Length    int        `json:"length"`
-	Duration  int        `json:"duration"`
-	Thumbnail *PhotoSize `json:"thumb"`     // optional
-	FileSize  int        `json:"file_size"` // optional
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Length       int        `json:"length"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumb"`     // optional
+	FileSize     int        `json:"file_size"` // optional
 }
 
 // Voice contains information about a voice.
 type Voice struct {
-	FileID   string `json:"file_id"`
-	Duration int    `json:"duration"`
-	MimeType string `json:"mime_type"` // optional
-	FileSize int    `json:"file_size"` // optional
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"`
+	MimeType     string `json:"mime_type"` // optional
+	FileSize     int    `json:"file_size"` // optional
 }
 
 // Contact contains information about a contact.
@@ -342,7 +458,7 @@ type Contact struct {
 	PhoneNumber string `json:"phone_number"`
 	FirstName   string `json:"first_name"`
 	LastName    string `json:"last_name"` // optional
-	UserID      int    `json:"user_id"`   // optional
+	UserID      int64  `json:"user_id"`   // optional
 }
 
 // Location contains information about a place.
@@ -367,9 +483,10 @@ type UserProfilePhotos struct {
 
 // File contains information about a file to download from Telegram.
 type File struct {
-	FileID   string `json:"file_id"`
-	FileSize int    `json:"file_size"` // optional
-	FilePath string `json:"file_path"` // optional
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int    `json:"file_size"` // optional
+	FilePath     string `json:"file_path"` // optional
 }
 
 // Link returns a full path to the download URL for a File.
@@ -381,17 +498,94 @@ func (f *File) Link(token string) string {
 
 // ReplyKeyboardMarkup allows the Bot to set a custom keyboard.
 type ReplyKeyboardMarkup struct {
-	Keyboard        [][]KeyboardButton `json:"keyboard"`
-	ResizeKeyboard  bool               `json:"resize_keyboard"`   // optional
-	OneTimeKeyboard bool               `json:"one_time_keyboard"` // optional
-	Selective       bool               `json:"selective"`         // optional
+	Keyboard              [][]KeyboardButton `json:"keyboard"`
+	IsPersistent          bool               `json:"is_persistent,omitempty"`           // optional
+	ResizeKeyboard        bool               `json:"resize_keyboard"`                   // optional
+	OneTimeKeyboard       bool               `json:"one_time_keyboard"`                 // optional
+	InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"` // optional
+	Selective             bool               `json:"selective"`                         // optional
 }
 
 // KeyboardButton is a button within a custom keyboard.
+//
+// At most one of the optional fields may be set.
 type KeyboardButton struct {
-	Text            string `json:"text"`
-	RequestContact  bool   `json:"request_contact"`
-	RequestLocation bool   `json:"request_location"`
+	Text            string                     `json:"text"`
+	RequestUser     *KeyboardButtonRequestUser `json:"request_user,omitempty"` // optional
+	RequestChat     *KeyboardButtonRequestChat `json:"request_chat,omitempty"` // optional
+	RequestContact  bool                       `json:"request_contact"`        // optional
+	RequestLocation bool                       `json:"request_location"`       // optional
+	RequestPoll     *KeyboardButtonPollType    `json:"request_poll,omitempty"` // optional
+	WebApp          *WebAppInfo                `json:"web_app,omitempty"`      // optional
+}
+
+// KeyboardButtonPollType restricts the type of poll a user may create
+// with a keyboard button.
+type KeyboardButtonPollType struct {
+	// Type is "quiz" or "regular", or empty to allow both.
+	Type string `json:"type,omitempty"`
+}
+
+// KeyboardButtonRequestUser defines the criteria used to request a
+// suitable user with a keyboard button. The identifier of the selected
+// user is shared with the bot.
+type KeyboardButtonRequestUser struct {
+	RequestID     int   `json:"request_id"`
+	UserIsBot     *bool `json:"user_is_bot,omitempty"`     // optional
+	UserIsPremium *bool `json:"user_is_premium,omitempty"` // optional
+}
+
+// KeyboardButtonRequestChat defines the criteria used to request a
+// suitable chat with a keyboard button. The identifier of the selected
+// chat is shared with the bot.
+type KeyboardButtonRequestChat struct {
+	RequestID               int                      `json:"request_id"`
+	ChatIsChannel           bool                     `json:"chat_is_channel"`
+	ChatIsForum             *bool                    `json:"chat_is_forum,omitempty"`             // optional
+	ChatHasUsername         *bool                    `json:"chat_has_username,omitempty"`         // optional
+	ChatIsCreated           bool                     `json:"chat_is_created,omitempty"`           // optional
+	UserAdministratorRights *ChatAdministratorRights `json:"user_administrator_rights,omitempty"` // optional
+	BotAdministratorRights  *ChatAdministratorRights `json:"bot_administrator_rights,omitempty"`  // optional
+	BotIsMember             bool                     `json:"bot_is_member,omitempty"`             // optional
+}
+
+// ChatAdministratorRights are the rights of an administrator in a chat.
+type ChatAdministratorRights struct {
+	IsAnonymous         bool `json:"is_anonymous"`
+	CanManageChat       bool `json:"can_manage_chat"`
+	CanDeleteMessages   bool `json:"can_delete_messages"`
+	CanManageVideoChats bool `json:"can_manage_video_chats"`
+	CanRestrictMembers  bool `json:"can_restrict_members"`
+	CanPromoteMembers   bool `json:"can_promote_members"`
+	CanChangeInfo       bool `json:"can_change_info"`
+	CanInviteUsers      bool `json:"can_invite_users"`
+	CanPostMessages     bool `json:"can_post_messages,omitempty"` // optional
+	CanEditMessages     bool `json:"can_edit_messages,omitempty"` // optional
+	CanPinMessages      bool `json:"can_pin_messages,omitempty"`  // optional
+	CanManageTopics     bool `json:"can_manage_topics,omitempty"` // optional
+}
+
+// WebAppInfo describes a Web App launched by a button.
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+// UserShared is the user selected with a KeyboardButtonRequestUser.
+type UserShared struct {
+	RequestID int   `json:"request_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+// ChatShared is the chat selected with a KeyboardButtonRequestChat.
+type ChatShared struct {
+	RequestID int   `json:"request_id"`
+	ChatID    int64 `json:"chat_id"`
+}
+
+// WebAppData is the data sent by a Web App launched by a KeyboardButton.
+type WebAppData struct {
+	Data       string `json:"data"`
+	ButtonText string `json:"button_text"`
 }
 
 // ReplyKeyboardHide allows the Bot to hide a custom keyboard.
@@ -417,15 +611,38 @@ type InlineKeyboardMarkup struct {
 // Note that some values are references as even an empty string
 // will change behavior.
 //
-// CallbackGame, if set, MUST be first button in first row.
+// Exactly one of the optional fields must be set. CallbackGame and Pay,
+// if set, MUST be first button in first row.
 type InlineKeyboardButton struct {
-	Text                         string        `json:"text"`
-	URL                          *string       `json:"url,omitempty"`                              // optional
-	CallbackData                 *string       `json:"callback_data,omitempty"`                    // optional
-	SwitchInlineQuery            *string       `json:"switch_inline_query,omitempty"`              // optional
-	SwitchInlineQueryCurrentChat *string       `json:"switch_inline_query_current_chat,omitempty"` // optional
-	CallbackGame                 *CallbackGame `json:"callback_game,omitempty"`                    // optional
-	Pay                          bool          `json:"pay,omitempty"`                              // optional
+	Text                         string                       `json:"text"`
+	URL                          *string                      `json:"url,omitempty"`                              // optional
+	CallbackData                 *string                      `json:"callback_data,omitempty"`                    // optional
+	WebApp                       *WebAppInfo                  `json:"web_app,omitempty"`                          // optional
+	LoginURL                     *LoginURL                    `json:"login_url,omitempty"`                        // optional
+	SwitchInlineQuery            *string                      `json:"switch_inline_query,omitempty"`              // optional
+	SwitchInlineQueryCurrentChat *string                      `json:"switch_inline_query_current_chat,omitempty"` // optional
+	SwitchInlineQueryChosenChat  *SwitchInlineQueryChosenChat `json:"switch_inline_query_chosen_chat,omitempty"`  // optional
+	CallbackGame                 *CallbackGame                `json:"callback_game,omitempty"`                    // optional
+	Pay                          bool                         `json:"pay,omitempty"`                              // optional
+}
+
+// LoginURL is a button which logs the user in to a website using the
+// Telegram Login Widget.
+type LoginURL struct {
+	URL                string `json:"url"`
+	ForwardText        string `json:"forward_text,omitempty"`         // optional
+	BotUsername        string `json:"bot_username,omitempty"`         // optional
+	RequestWriteAccess bool   `json:"request_write_access,omitempty"` // optional
+}
+
+// SwitchInlineQueryChosenChat is a button which lets the user choose a
+// chat of the given types to start an inline query in.
+type SwitchInlineQueryChosenChat struct {
+	Query             string `json:"query,omitempty"`               // optional
+	AllowUserChats    bool   `json:"allow_user_chats,omitempty"`    // optional
+	AllowBotChats     bool   `json:"allow_bot_chats,omitempty"`     // optional
+	AllowGroupChats   bool   `json:"allow_group_chats,omitempty"`   // optional
+	AllowChannelChats bool   `json:"allow_channel_chats,omitempty"` // optional
 }
 
 // CallbackQuery is data sent when a keyboard button with callback data
@@ -443,8 +660,9 @@ type CallbackQuery struct {
 // ForceReply allows the Bot to have users directly reply to it without
 // additional interaction.
 type ForceReply struct {
-	ForceReply bool `json:"force_reply"`
-	Selective  bool `json:"selective"` // optional
+	ForceReply            bool   `json:"force_reply"`
+	InputFieldPlaceholder string `json:"input_field_placeholder,omitempty"` // optional
+	Selective             bool   `json:"selective"`                         // optional
 }
 
 // ChatMember is information about a member in a chat.
@@ -526,24 +744,68 @@ func (info WebhookInfo) IsSet() bool {
 }
 
 // InputMediaPhoto contains a photo for displaying as part of a media group.
+//
+// Media may be a file ID or URL string, or a FilePath, FileBytes or
+// FileReader to upload.
 type InputMediaPhoto struct {
-	Type      string `json:"type"`
-	Media     string `json:"media"`
-	Caption   string `json:"caption"`
-	ParseMode string `json:"parse_mode"`
+	Type            string          `json:"type"`
+	Media           interface{}     `json:"media"`
+	Caption         string          `json:"caption"`
+	ParseMode       string          `json:"parse_mode"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 }
 
 // InputMediaVideo contains a video for displaying as part of a media group.
+//
+// Thumb may be a FilePath, FileBytes or FileReader to upload alongside the
+// video.
 type InputMediaVideo struct {
-	Type  string `json:"type"`
-	Media string `json:"media"`
-	// thumb intentionally missing as it is not currently compatible
-	Caption           string `json:"caption"`
-	ParseMode         string `json:"parse_mode"`
-	Width             int    `json:"width"`
-	Height            int    `json:"height"`
-	Duration          int    `json:"duration"`
-	SupportsStreaming bool   `json:"supports_streaming"`
+	Type              string          `json:"type"`
+	Media             interface{}     `json:"media"`
+	Thumb             interface{}     `json:"thumb,omitempty"`
+	Caption           string          `json:"caption"`
+	ParseMode         string          `json:"parse_mode"`
+	CaptionEntities   []MessageEntity `json:"caption_entities,omitempty"`
+	Width             int             `json:"width"`
+	Height            int             `json:"height"`
+	Duration          int             `json:"duration"`
+	SupportsStreaming bool            `json:"supports_streaming"`
+}
+
+// InputMediaAnimation contains an animation. It may only be used to edit
+// a message, not as part of a media group.
+type InputMediaAnimation struct {
+	Type            string          `json:"type"`
+	Media           interface{}     `json:"media"`
+	Caption         string          `json:"caption"`
+	ParseMode       string          `json:"parse_mode"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	Width           int             `json:"width"`
+	Height          int             `json:"height"`
+	Duration        int             `json:"duration"`
+}
+
+// InputMediaAudio contains an audio file for displaying as part of a media
+// group. Audio files can only be grouped with other audio files.
+type InputMediaAudio struct {
+	Type            string          `json:"type"`
+	Media           interface{}     `json:"media"`
+	Caption         string          `json:"caption"`
+	ParseMode       string          `json:"parse_mode"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	Duration        int             `json:"duration"`
+	Performer       string          `json:"performer"`
+	Title           string          `json:"title"`
+}
+
+// InputMediaDocument contains a document for displaying as part of a media
+// group. Documents can only be grouped with other documents.
+type InputMediaDocument struct {
+	Type            string          `json:"type"`
+	Media           interface{}     `json:"media"`
+	Caption         string          `json:"caption"`
+	ParseMode       string          `json:"parse_mode"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 }
 
 // InlineQuery is a Query from Telegram for an inline request.
@@ -555,6 +817,20 @@ type InlineQuery struct {
 	Offset   string    `json:"offset"`
 }
 
+// InlineQueryResult is one of the InlineQueryResult types, such as
+// InlineQueryResultArticle, which can be sent in an InlineConfig.
+//
+// The Type field of a result is set to ResultType when it is sent if it
+// is empty.
+type InlineQueryResult interface {
+	// ResultType returns the type of the result, such as "article".
+	ResultType() string
+	// ResultID returns the unique ID of the result.
+	ResultID() string
+
+	inlineQueryResult()
+}
+
 // InlineQueryResultArticle is an inline query response article.
 type InlineQueryResultArticle struct {
 	Type                string                `json:"type"`                            // required
@@ -582,6 +858,8 @@ type InlineQueryResultPhoto struct {
 	Title               string                `json:"title"`
 	Description         string                `json:"description"`
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -595,6 +873,7 @@ type InlineQueryResultCachedPhoto struct {
 	Description         string                `json:"description"`
 	Caption             string                `json:"caption"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -610,6 +889,8 @@ type InlineQueryResultGIF struct {
 	ThumbURL            string                `json:"thumb_url"`
 	Title               string                `json:"title"`
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -622,6 +903,7 @@ type InlineQueryResultCachedGIF struct {
 	Title               string                `json:"title"`
 	Caption             string                `json:"caption"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -637,6 +919,8 @@ type InlineQueryResultMPEG4GIF struct {
 	ThumbURL            string                `json:"thumb_url"`
 	Title               string                `json:"title"`
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -650,6 +934,7 @@ type InlineQueryResultCachedMpeg4Gif struct {
 	Title               string                `json:"title"`
 	Caption             string                `json:"caption"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -663,6 +948,8 @@ type InlineQueryResultVideo struct {
 	ThumbURL            string                `json:"thumb_url"`
 	Title               string                `json:"title"`
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	Width               int                   `json:"video_width"`
 	Height              int                   `json:"video_height"`
 	Duration            int                   `json:"video_duration"`
@@ -680,6 +967,7 @@ type InlineQueryResultCachedVideo struct {
 	Description         string                `json:"description"`
 	Caption             string                `json:"caption"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -691,6 +979,8 @@ type InlineQueryResultAudio struct {
 	URL                 string                `json:"audio_url"` // required
 	Title               string                `json:"title"`     // required
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	Performer           string                `json:"performer"`
 	Duration            int                   `json:"audio_duration"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
@@ -704,6 +994,7 @@ type InlineQueryResultCachedAudio struct {
 	AudioID             string                `json:"audio_file_id"` // required
 	Caption             string                `json:"caption"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -715,6 +1006,8 @@ type InlineQueryResultVoice struct {
 	URL                 string                `json:"voice_url"` // required
 	Title               string                `json:"title"`     // required
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	Duration            int                   `json:"voice_duration"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
@@ -728,6 +1021,7 @@ type InlineQueryResultCachedVoice struct {
 	Title               string                `json:"title"`         // required
 	Caption             string                `json:"caption"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
@@ -738,6 +1032,8 @@ type InlineQueryResultDocument struct {
 	ID                  string                `json:"id"`    // required
 	Title               string                `json:"title"` // required
 	Caption             string                `json:"caption"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	URL                 string                `json:"document_url"` // required
 	MimeType            string                `json:"mime_type"`    // required
 	Description         string                `json:"description"`
@@ -757,22 +1053,71 @@ type InlineQueryResultCachedDocument struct {
 	Caption             string                `json:"caption"`
 	Description         string                `json:"description"`
 	ParseMode           string                `json:"parse_mode"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
 }
 
 // InlineQueryResultLocation is an inline query response location.
 type InlineQueryResultLocation struct {
+	Type                 string                `json:"type"`      // required
+	ID                   string                `json:"id"`        // required
+	Latitude             float64               `json:"latitude"`  // required
+	Longitude            float64               `json:"longitude"` // required
+	Title                string                `json:"title"`     // required
+	HorizontalAccuracy   float64               `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int                   `json:"live_period,omitempty"`
+	Heading              int                   `json:"heading,omitempty"`
+	ProximityAlertRadius int                   `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent  interface{}           `json:"input_message_content,omitempty"`
+	ThumbURL             string                `json:"thumb_url"`
+	ThumbWidth           int                   `json:"thumb_width"`
+	ThumbHeight          int                   `json:"thumb_height"`
+}
+
+// InlineQueryResultVenue is an inline query response venue.
+type InlineQueryResultVenue struct {
 	Type                string                `json:"type"`      // required
 	ID                  string                `json:"id"`        // required
 	Latitude            float64               `json:"latitude"`  // required
 	Longitude           float64               `json:"longitude"` // required
 	Title               string                `json:"title"`     // required
+	Address             string                `json:"address"`   // required
+	FoursquareID        string                `json:"foursquare_id,omitempty"`
+	FoursquareType      string                `json:"foursquare_type,omitempty"`
+	GooglePlaceID       string                `json:"google_place_id,omitempty"`
+	GooglePlaceType     string                `json:"google_place_type,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
+	ThumbURL            string                `json:"thumb_url,omitempty"`
+	ThumbWidth          int                   `json:"thumb_width,omitempty"`
+	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultContact is an inline query response contact.
+type InlineQueryResultContact struct {
+	Type                string                `json:"type"`         // required
+	ID                  string                `json:"id"`           // required
+	PhoneNumber         string                `json:"phone_number"` // required
+	FirstName           string                `json:"first_name"`   // required
+	LastName            string                `json:"last_name,omitempty"`
+	VCard               string                `json:"vcard,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
+	ThumbURL            string                `json:"thumb_url,omitempty"`
+	ThumbWidth          int                   `json:"thumb_width,omitempty"`
+	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultCachedSticker is an inline query response with cached
+// sticker.
+type InlineQueryResultCachedSticker struct {
+	Type                string                `json:"type"`            // required
+	ID                  string                `json:"id"`              // required
+	StickerID           string                `json:"sticker_file_id"` // required
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent interface{}           `json:"input_message_content,omitempty"`
-	ThumbURL            string                `json:"thumb_url"`
-	ThumbWidth          int                   `json:"thumb_width"`
-	ThumbHeight         int                   `json:"thumb_height"`
 }
 
 // InlineQueryResultGame is an inline query response game.
@@ -783,6 +1128,246 @@ type InlineQueryResultGame struct {
 	ReplyMarkup   *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
+// ResultType returns "article".
+func (result InlineQueryResultArticle) ResultType() string {
+	return "article"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultArticle) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultArticle) inlineQueryResult() {}
+
+// ResultType returns "photo".
+func (result InlineQueryResultPhoto) ResultType() string {
+	return "photo"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultPhoto) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultPhoto) inlineQueryResult() {}
+
+// ResultType returns "photo".
+func (result InlineQueryResultCachedPhoto) ResultType() string {
+	return "photo"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedPhoto) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedPhoto) inlineQueryResult() {}
+
+// ResultType returns "gif".
+func (result InlineQueryResultGIF) ResultType() string {
+	return "gif"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultGIF) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultGIF) inlineQueryResult() {}
+
+// ResultType returns "gif".
+func (result InlineQueryResultCachedGIF) ResultType() string {
+	return "gif"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedGIF) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedGIF) inlineQueryResult() {}
+
+// ResultType returns "mpeg4_gif".
+func (result InlineQueryResultMPEG4GIF) ResultType() string {
+	return "mpeg4_gif"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultMPEG4GIF) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultMPEG4GIF) inlineQueryResult() {}
+
+// ResultType returns "mpeg4_gif".
+func (result InlineQueryResultCachedMpeg4Gif) ResultType() string {
+	return "mpeg4_gif"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedMpeg4Gif) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedMpeg4Gif) inlineQueryResult() {}
+
+// ResultType returns "video".
+func (result InlineQueryResultVideo) ResultType() string {
+	return "video"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultVideo) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultVideo) inlineQueryResult() {}
+
+// ResultType returns "video".
+func (result InlineQueryResultCachedVideo) ResultType() string {
+	return "video"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedVideo) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedVideo) inlineQueryResult() {}
+
+// ResultType returns "audio".
+func (result InlineQueryResultAudio) ResultType() string {
+	return "audio"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultAudio) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultAudio) inlineQueryResult() {}
+
+// ResultType returns "audio".
+func (result InlineQueryResultCachedAudio) ResultType() string {
+	return "audio"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedAudio) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedAudio) inlineQueryResult() {}
+
+// ResultType returns "voice".
+func (result InlineQueryResultVoice) ResultType() string {
+	return "voice"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultVoice) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultVoice) inlineQueryResult() {}
+
+// ResultType returns "voice".
+func (result InlineQueryResultCachedVoice) ResultType() string {
+	return "voice"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedVoice) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedVoice) inlineQueryResult() {}
+
+// ResultType returns "document".
+func (result InlineQueryResultDocument) ResultType() string {
+	return "document"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultDocument) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultDocument) inlineQueryResult() {}
+
+// ResultType returns "document".
+func (result InlineQueryResultCachedDocument) ResultType() string {
+	return "document"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedDocument) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedDocument) inlineQueryResult() {}
+
+// ResultType returns "location".
+func (result InlineQueryResultLocation) ResultType() string {
+	return "location"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultLocation) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultLocation) inlineQueryResult() {}
+
+// ResultType returns "venue".
+func (result InlineQueryResultVenue) ResultType() string {
+	return "venue"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultVenue) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultVenue) inlineQueryResult() {}
+
+// ResultType returns "contact".
+func (result InlineQueryResultContact) ResultType() string {
+	return "contact"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultContact) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultContact) inlineQueryResult() {}
+
+// ResultType returns "sticker".
+func (result InlineQueryResultCachedSticker) ResultType() string {
+	return "sticker"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultCachedSticker) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultCachedSticker) inlineQueryResult() {}
+
+// ResultType returns "game".
+func (result InlineQueryResultGame) ResultType() string {
+	return "game"
+}
+
+// ResultID returns the ID of the result.
+func (result InlineQueryResultGame) ResultID() string {
+	return result.ID
+}
+
+func (result InlineQueryResultGame) inlineQueryResult() {}
+
 // ChosenInlineResult is an inline query result chosen by a User
 type ChosenInlineResult struct {
 	ResultID        string    `json:"result_id"`
@@ -795,9 +1380,10 @@ type ChosenInlineResult struct {
 // InputTextMessageContent contains text for displaying
 // as an inline query result.
 type InputTextMessageContent struct {
-	Text                  string `json:"message_text"`
-	ParseMode             string `json:"parse_mode"`
-	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+	Text                  string          `json:"message_text"`
+	ParseMode             string          `json:"parse_mode"`
+	Entities              []MessageEntity `json:"entities,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview"`
 }
 
 // InputLocationMessageContent contains a location for displaying
@@ -825,6 +1411,31 @@ type InputContactMessageContent struct {
 	LastName    string `json:"last_name"`
 }
 
+// InputInvoiceMessageContent contains an invoice for displaying as an
+// inline query result.
+type InputInvoiceMessageContent struct {
+	Title                     string         `json:"title"`
+	Description               string         `json:"description"`
+	Payload                   string         `json:"payload"`
+	ProviderToken             string         `json:"provider_token"`
+	Currency                  string         `json:"currency"`
+	Prices                    []LabeledPrice `json:"prices"`
+	MaxTipAmount              int            `json:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       []int          `json:"suggested_tip_amounts,omitempty"`
+	ProviderData              string         `json:"provider_data,omitempty"`
+	PhotoURL                  string         `json:"photo_url,omitempty"`
+	PhotoSize                 int            `json:"photo_size,omitempty"`
+	PhotoWidth                int            `json:"photo_width,omitempty"`
+	PhotoHeight               int            `json:"photo_height,omitempty"`
+	NeedName                  bool           `json:"need_name,omitempty"`
+	NeedPhoneNumber           bool           `json:"need_phone_number,omitempty"`
+	NeedEmail                 bool           `json:"need_email,omitempty"`
+	NeedShippingAddress       bool           `json:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider bool           `json:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       bool           `json:"send_email_to_provider,omitempty"`
+	IsFlexible                bool           `json:"is_flexible,omitempty"`
+}
+
 // Invoice contains basic information about an invoice.
 type Invoice struct {
 	Title          string `json:"title"`
@@ -904,3 +1515,81 @@ type Error struct {
 func (e Error) Error() string {
 	return e.Message
 }
+
+// isNotModified returns if err reports that an edited message did not
+// change, which Telegram treats as an error.
+func isNotModified(err error) bool {
+	apiErr, ok := err.(Error)
+	return ok && strings.Contains(apiErr.Message, "message is not modified")
+}
+
+// isFileIDError returns if err reports that Telegram does not accept a file
+// ID, for example because it belongs to another bot or expired.
+func isFileIDError(err error) bool {
+	apiErr, ok := err.(Error)
+	return ok && (strings.Contains(apiErr.Message, "file identifier") ||
+		strings.Contains(apiErr.Message, "FILE_REFERENCE_"))
+}
+
+// DecodeError is returned when the result of a request could not be
+// decoded.
+type DecodeError struct {
+	Method  string
+	Payload string // excerpt of the undecodable result
+	Err     error
+}
+
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("decoding result of %s: %v: %s", e.Method, e.Err, e.Payload)
+}
+
+// Unwrap returns the error of the JSON decoder.
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// maxExcerptLength is how much of a payload is included in a DecodeError.
+const maxExcerptLength = 256
+
+// excerpt returns the beginning of payload.
+func excerpt(payload []byte) string {
+	if len(payload) <= maxExcerptLength {
+		return string(payload)
+	}
+
+	return string(payload[:maxExcerptLength]) + "..."
+}
+
+// FileTooBigError is returned when downloading a file larger than the
+// allowed size.
+type FileTooBigError struct {
+	FileID string
+	Size   int64 // size of the file, or the bytes read until it was detected
+	Limit  int64
+}
+
+func (e FileTooBigError) Error() string {
+	return fmt.Sprintf("file %s is too big: %d bytes, limit is %d bytes", e.FileID, e.Size, e.Limit)
+}
+
+// FileSizeMismatchError is returned when a downloaded file does not have
+// the size reported by Telegram.
+type FileSizeMismatchError struct {
+	FileID   string
+	Expected int64
+	Actual   int64
+}
+
+func (e FileSizeMismatchError) Error() string {
+	return fmt.Sprintf("file %s has %d bytes, expected %d bytes", e.FileID, e.Actual, e.Expected)
+}
+
+// CallbackExpiredError is returned when decoding callback data whose
+// payload expired from the CallbackStore.
+type CallbackExpiredError struct {
+	Key string
+}
+
+func (e CallbackExpiredError) Error() string {
+	return ErrCallbackExpired
+}
@@ -1,6 +1,10 @@
 package tgbotapi_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
 	"testing"
 	"time"
 
@@ -198,3 +202,82 @@ func TestFileLink(t *testing.T) {
 		t.Fail()
 	}
 }
+
+// decodeFixture strictly decodes a recorded JSON fixture into v, so that
+// fields missing from our types make the test fail, and then checks that
+// v survives being encoded and decoded again.
+func decodeFixture(t *testing.T, path string, v interface{}) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+	if err := json.Unmarshal(encoded, again); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(v, again) {
+		t.Errorf("%s did not survive a round trip", path)
+	}
+}
+
+func TestMessageFixtureRoundTrip(t *testing.T) {
+	var message tgbotapi.Message
+	decodeFixture(t, "tests/message.json", &message)
+
+	if message.MediaGroupID != "13449148392838434" ||
+		message.AuthorSignature != "Author" ||
+		message.ForwardSignature != "Editor" ||
+		message.ViaBot == nil || message.ViaBot.UserName != "testbot" ||
+		message.SenderChat == nil || !message.SenderChat.IsChannel() ||
+		!message.HasProtectedContent ||
+		!message.IsAutomaticForward ||
+		message.ReplyMarkup == nil || *message.ReplyMarkup.InlineKeyboard[0][1].CallbackData != "press" {
+		t.Fail()
+	}
+
+	if message.CaptionHTML() != `😀 <b>Caption</b> with a <a href="https://core.telegram.org/bots/api">link</a>` {
+		t.Error(message.CaptionHTML())
+	}
+}
+
+func TestChatFixtureRoundTrip(t *testing.T) {
+	var chat tgbotapi.Chat
+	decodeFixture(t, "tests/chat.json", &chat)
+
+	if !chat.IsSuperGroup() ||
+		!chat.IsForum ||
+		len(chat.ActiveUsernames) != 2 ||
+		chat.Permissions == nil || !chat.Permissions.CanSendPolls ||
+		chat.PinnedMessage == nil || chat.PinnedMessage.Text != "Pinned" ||
+		chat.SlowModeDelay != 30 ||
+		chat.Location == nil || chat.Location.Address != "Amsterdam" {
+		t.Fail()
+	}
+}
+
+func TestUserFixtureRoundTrip(t *testing.T) {
+	var user tgbotapi.User
+	decodeFixture(t, "tests/user.json", &user)
+
+	if !user.IsBot || !user.CanJoinGroups || !user.SupportsInlineQueries {
+		t.Fail()
+	}
+
+	// User IDs may need more than 32 bits.
+	if user.ID != 6153667468 {
+		t.Error(user.ID)
+	}
+}